@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"slices"
+)
+
+var (
+	errInvalidBinary  = errors.New("sets: invalid binary encoding")
+	errBinaryChecksum = errors.New("sets: binary encoding checksum mismatch")
+	errBinaryKind     = errors.New("sets: binary encoding element kind mismatch")
+)
+
+// binaryVersion is the version of the binary encoding of a set.
+const binaryVersion = 1
+
+// Element kinds of the binary encoding.
+const (
+	binaryGob    = 0 // A gob encoded list of elements.
+	binaryInt    = 1 // Sorted signed integers: a zigzag varint followed by uvarint deltas.
+	binaryUint   = 2 // Sorted unsigned integers: a uvarint followed by uvarint deltas.
+	binaryString = 3 // Sorted strings: each a uvarint length followed by its bytes.
+)
+
+// Binary is a wrapper that encodes a set in a compact binary form and decodes it into a set.
+// It implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler, which are also
+// used by encoding/gob.
+//
+// The encoding begins with a version and the kind of the elements, which are sorted if
+// their underlying type is an integer or string, and ends with a checksum, so decoding
+// corrupted data returns an error. Integers are encoded as varint deltas and strings
+// are prefixed with their length. Elements of other types are encoded with encoding/gob.
+type Binary[E any] struct {
+	// Set is the set to encode or into which to decode. Decoding replaces
+	// its elements, but preserves its type and comparison function.
+	// It must not be nil when decoding.
+	Set Set[E]
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (b Binary[E]) MarshalBinary() ([]byte, error) {
+	if b.Set == nil {
+		return nil, errors.New("sets: Binary.Set is nil")
+	}
+	elems := b.Set.Elems()
+	kind := binaryKind[E]()
+	buf := []byte{binaryVersion, kind}
+	buf = binary.AppendUvarint(buf, uint64(len(elems)))
+	switch kind {
+	case binaryInt:
+		vals := make([]int64, len(elems))
+		for i, e := range elems {
+			vals[i] = reflect.ValueOf(e).Int()
+		}
+		slices.Sort(vals)
+		prev := uint64(0)
+		for i, v := range vals {
+			if i == 0 {
+				buf = binary.AppendVarint(buf, v)
+			} else {
+				buf = binary.AppendUvarint(buf, uint64(v)-prev) // Two's complement handles overflow.
+			}
+			prev = uint64(v)
+		}
+	case binaryUint:
+		vals := make([]uint64, len(elems))
+		for i, e := range elems {
+			vals[i] = reflect.ValueOf(e).Uint()
+		}
+		slices.Sort(vals)
+		prev := uint64(0)
+		for _, v := range vals {
+			buf = binary.AppendUvarint(buf, v-prev)
+			prev = v
+		}
+	case binaryString:
+		vals := make([]string, len(elems))
+		for i, e := range elems {
+			vals[i] = reflect.ValueOf(e).String()
+		}
+		slices.Sort(vals)
+		for _, v := range vals {
+			buf = binary.AppendUvarint(buf, uint64(len(v)))
+			buf = append(buf, v...)
+		}
+	default:
+		var w bytes.Buffer
+		if err := gob.NewEncoder(&w).Encode(elems); err != nil {
+			return nil, err
+		}
+		buf = append(buf, w.Bytes()...)
+	}
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It returns an error without modifying the set if the data is invalid.
+func (b *Binary[E]) UnmarshalBinary(data []byte) error {
+	if b.Set == nil {
+		return errors.New("sets: Binary.Set is nil")
+	}
+	if len(data) < 6 {
+		return errInvalidBinary
+	}
+	data, sum := data[:len(data)-4], binary.LittleEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(data) != sum {
+		return errBinaryChecksum
+	}
+	if data[0] != binaryVersion {
+		return errInvalidBinary
+	}
+	if kind := binaryKind[E](); data[1] != kind {
+		return errBinaryKind
+	}
+	kind, data := data[1], data[2:]
+	n, k := binary.Uvarint(data)
+	if k <= 0 || n > uint64(len(data)) { // Every element takes at least one byte.
+		return errInvalidBinary
+	}
+	data = data[k:]
+
+	elems := make([]E, n)
+	switch kind {
+	case binaryInt, binaryUint:
+		prev := uint64(0)
+		for i := range elems {
+			var v uint64
+			if i == 0 && kind == binaryInt {
+				x, k := binary.Varint(data)
+				if k <= 0 {
+					return errInvalidBinary
+				}
+				v, data = uint64(x), data[k:]
+			} else {
+				delta, k := binary.Uvarint(data)
+				if k <= 0 {
+					return errInvalidBinary
+				}
+				v, data = prev+delta, data[k:]
+			}
+			prev = v
+			e := reflect.ValueOf(&elems[i]).Elem()
+			if kind == binaryInt {
+				if e.OverflowInt(int64(v)) {
+					return errInvalidBinary
+				}
+				e.SetInt(int64(v))
+			} else {
+				if e.OverflowUint(v) {
+					return errInvalidBinary
+				}
+				e.SetUint(v)
+			}
+		}
+	case binaryString:
+		for i := range elems {
+			size, k := binary.Uvarint(data)
+			if k <= 0 || size > uint64(len(data)-k) {
+				return errInvalidBinary
+			}
+			reflect.ValueOf(&elems[i]).Elem().SetString(string(data[k : k+int(size)]))
+			data = data[k+int(size):]
+		}
+	default:
+		r := bytes.NewReader(data)
+		if err := gob.NewDecoder(r).Decode(&elems); err != nil || uint64(len(elems)) != n {
+			return errInvalidBinary
+		}
+		data = data[len(data)-r.Len():]
+	}
+	if len(data) != 0 {
+		return errInvalidBinary
+	}
+	return replace(b.Set, elems, false)
+}
+
+// binaryKind returns the kind of the binary encoding of elements of type E.
+func binaryKind[E any]() byte {
+	switch reflect.TypeFor[E]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binaryInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binaryUint
+	case reflect.String:
+		return binaryString
+	}
+	return binaryGob
+}
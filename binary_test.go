@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"math"
+	"slices"
+	"testing"
+)
+
+func testBinaryRoundTrip[E cmp.Ordered](t *testing.T, name string, elems ...E) {
+	t.Run(name, func(t *testing.T) {
+		data, err := Binary[E]{NewSorted(elems...)}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(); unexpected error: %v", err)
+		}
+		set := NewSorted[E]()
+		if err := (&Binary[E]{set}).UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(); unexpected error: %v", err)
+		}
+		want := slices.Sorted(slices.Values(elems))
+		if got := set.Elems(); !slices.Equal(got, want) {
+			t.Errorf("set.Elems(); got: %v; want: %v", got, want)
+		}
+	})
+}
+
+type binaryPoint struct{ X, Y int }
+
+func TestBinary(t *testing.T) {
+	testBinaryRoundTrip[int](t, "empty")
+	testBinaryRoundTrip(t, "int", -5, 3, 100, 0, 7)
+	testBinaryRoundTrip(t, "int64", int64(math.MinInt64), -1, 0, math.MaxInt64)
+	testBinaryRoundTrip(t, "int8", int8(math.MinInt8), 0, math.MaxInt8)
+	testBinaryRoundTrip(t, "uint64", uint64(0), 1, math.MaxUint64)
+	testBinaryRoundTrip(t, "string", "", "b", "a", "hello, 世界")
+	testBinaryRoundTrip(t, "float", -1.5, 0, math.Inf(1))
+
+	t.Run("gob", func(t *testing.T) {
+		data, err := Binary[binaryPoint]{New(binaryPoint{1, 2}, binaryPoint{3, 4})}.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary(); unexpected error: %v", err)
+		}
+		set := New[binaryPoint]()
+		if err := (&Binary[binaryPoint]{set}).UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(); unexpected error: %v", err)
+		}
+		if set.Len() != 2 || !set.ContainsAll(binaryPoint{1, 2}, binaryPoint{3, 4}) {
+			t.Errorf("set.Elems(); got: %v; want: [{1 2} {3 4}]", set.Elems())
+		}
+	})
+}
+
+func TestBinaryCompact(t *testing.T) {
+	set := NewSorted[uint32]()
+	for i := range uint32(1000) {
+		set.Insert(1_000_000 + 3*i)
+	}
+	data, err := Binary[uint32]{set}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(); unexpected error: %v", err)
+	}
+	// Header, count, first element, deltas, and checksum.
+	if got, want := len(data), 2+2+3+999+4; got != want {
+		t.Errorf("len(data); got: %v; want: %v", got, want)
+	}
+}
+
+func TestBinaryCorrupt(t *testing.T) {
+	data, err := Binary[string]{New("a", "b", "c")}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(); unexpected error: %v", err)
+	}
+	set := New("x")
+	for i := range data {
+		bad := slices.Clone(data)
+		bad[i] ^= 0x10
+		if err := (&Binary[string]{set}).UnmarshalBinary(bad); err == nil {
+			t.Errorf("UnmarshalBinary(byte %d flipped); expected error", i)
+		}
+	}
+	for n := range len(data) {
+		if err := (&Binary[string]{set}).UnmarshalBinary(data[:n]); err == nil {
+			t.Errorf("UnmarshalBinary(%d bytes); expected error", n)
+		}
+	}
+	if err := (&Binary[int]{New[int]()}).UnmarshalBinary(data); err == nil {
+		t.Errorf("UnmarshalBinary(wrong kind); expected error")
+	}
+	if got := set.Elems(); !slices.Equal(got, []string{"x"}) {
+		t.Errorf("set.Elems() after errors; got: %v; want: [x]", got)
+	}
+
+	neg, err := Binary[int]{New(2, -1)}.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(); unexpected error: %v", err)
+	}
+	bits := Synchronized(NewBitSet(1))
+	if err := (&Binary[int]{bits}).UnmarshalBinary(neg); err == nil {
+		t.Errorf("UnmarshalBinary(negative into bit set); expected error")
+	}
+	if got := bits.Elems(); !slices.Equal(got, []int{1}) {
+		t.Errorf("bits.Elems() after error; got: %v; want: [1]", got)
+	}
+}
+
+func TestBinaryGob(t *testing.T) {
+	type cache struct {
+		Name string
+		Tags Binary[string]
+	}
+	var buf bytes.Buffer
+	in := cache{Name: "test", Tags: Binary[string]{New("a", "b")}}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("gob.Encode(); unexpected error: %v", err)
+	}
+	out := cache{Tags: Binary[string]{NewSortedBTree[string]()}}
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("gob.Decode(); unexpected error: %v", err)
+	}
+	if got := out.Tags.Set.Elems(); out.Name != "test" || !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("gob.Decode(); got: %v, %v; want: test, [a b]", out.Name, got)
+	}
+}
@@ -0,0 +1,475 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// NewBitSet returns a sorted set initialized with the given elements.
+// It's backed by a dense bit set, so its memory usage is proportional to its
+// greatest element and it's best suited for small non-negative integers.
+// Set operations between bit sets operate on 64 elements at a time, and it keeps
+// a count of the elements in each word so that Rank, At, and CountRange take
+// O(log n) time in its number of words.
+//
+// It panics if a negative element is inserted.
+func NewBitSet[E constraints.Integer](elems ...E) Sorted[E] {
+	set := &bitset[E]{}
+	set.InsertAll(elems...)
+	return set
+}
+
+type bitset[E constraints.Integer] struct {
+	words  []uint64
+	counts fenwick // Number of bits set in each word.
+}
+
+func (set *bitset[E]) Contains(elem E) bool {
+	if elem < 0 {
+		return false
+	}
+	return set.has(uint64(elem))
+}
+
+func (set *bitset[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if !set.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *bitset[E]) ContainsSet(other Set[E]) bool {
+	if other, ok := other.(*bitset[E]); ok {
+		for i, w := range other.words {
+			if w&^set.word(i) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	ok := true
+	other.Range(func(e E) bool {
+		ok = set.Contains(e)
+		return ok
+	})
+	return ok
+}
+
+func (set *bitset[E]) isDisjoint(other Set[E]) (disjoint, ok bool) {
+	o, ok := other.(*bitset[E])
+	if !ok {
+		return false, false
+	}
+	for i := range min(len(set.words), len(o.words)) {
+		if set.words[i]&o.words[i] != 0 {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func (set *bitset[E]) Insert(elem E) {
+	if elem < 0 {
+		panic("sets: negative element in bit set")
+	}
+	i := uint64(elem)
+	if set.has(i) {
+		return
+	}
+	set.grow(int(i/64) + 1)
+	set.words[i/64] |= 1 << (i % 64)
+	set.counts.add(int(i/64), 1)
+}
+
+func (set *bitset[E]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		set.Insert(e)
+	}
+}
+
+func (set *bitset[E]) InsertSet(other Set[E]) {
+	if other, ok := other.(*bitset[E]); ok {
+		set.grow(len(other.words))
+		for i, w := range other.words {
+			set.words[i] |= w
+		}
+		set.recount()
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Insert(e)
+		return true
+	})
+}
+
+func (set *bitset[E]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set.Insert(e)
+	}
+}
+
+func (set *bitset[E]) Remove(elem E) {
+	if elem < 0 {
+		return
+	}
+	if i := uint64(elem); set.has(i) {
+		set.words[i/64] &^= 1 << (i % 64)
+		set.counts.add(int(i/64), -1)
+		set.trim()
+	}
+}
+
+func (set *bitset[E]) RemoveAll(elems ...E) {
+	for _, e := range elems {
+		set.Remove(e)
+	}
+}
+
+func (set *bitset[E]) RemoveSet(other Set[E]) {
+	if other, ok := other.(*bitset[E]); ok {
+		for i := range min(len(set.words), len(other.words)) {
+			set.words[i] &^= other.words[i]
+		}
+		set.trim()
+		set.recount()
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Remove(e)
+		return true
+	})
+}
+
+func (set *bitset[E]) Intersection(other Set[E]) Set[E] {
+	s := &bitset[E]{}
+	if other, ok := other.(*bitset[E]); ok {
+		s.words = make([]uint64, min(len(set.words), len(other.words)))
+		for i := range s.words {
+			s.words[i] = set.words[i] & other.words[i]
+		}
+		s.trim()
+		s.recount()
+		return s
+	}
+	for e := range set.All() {
+		if other.Contains(e) {
+			s.Insert(e)
+		}
+	}
+	return s
+}
+
+func (set *bitset[E]) Union(other Set[E]) Set[E] {
+	s := set.clone()
+	s.InsertSet(other)
+	return s
+}
+
+func (set *bitset[E]) Difference(other Set[E]) Set[E] {
+	s := set.clone()
+	if other, ok := other.(*bitset[E]); ok {
+		s.RemoveSet(other)
+		return s
+	}
+	for e := range set.All() {
+		if other.Contains(e) {
+			s.Remove(e)
+		}
+	}
+	return s
+}
+
+func (set *bitset[E]) SymmetricDifference(other Set[E]) Set[E] {
+	s := set.clone()
+	if other, ok := other.(*bitset[E]); ok {
+		s.grow(len(other.words))
+		for i, w := range other.words {
+			s.words[i] ^= w
+		}
+		s.trim()
+		s.recount()
+		return s
+	}
+	other.Range(func(e E) bool {
+		if set.Contains(e) {
+			s.Remove(e)
+		} else {
+			s.Insert(e)
+		}
+		return true
+	})
+	return s
+}
+
+func (set *bitset[E]) Len() int {
+	return set.counts.sum(len(set.counts))
+}
+
+func (set *bitset[E]) Elems() []E {
+	elems := make([]E, 0, set.Len())
+	for e := range set.All() {
+		elems = append(elems, e)
+	}
+	return elems
+}
+
+func (set *bitset[E]) Range(fn func(v E) bool) {
+	for e := range set.All() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (set *bitset[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i, w := range set.words {
+			for ; w != 0; w &= w - 1 {
+				if !yield(E(i*64 + bits.TrailingZeros64(w))) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (set *bitset[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := len(set.words) - 1; i >= 0; i-- {
+			for w := set.words[i]; w != 0; {
+				k := 63 - bits.LeadingZeros64(w)
+				if !yield(E(i*64 + k)) {
+					return
+				}
+				w &^= 1 << k
+			}
+		}
+	}
+}
+
+func (set *bitset[E]) Min() (E, bool) {
+	return set.next(0)
+}
+
+func (set *bitset[E]) Max() (E, bool) {
+	return set.prev(math.MaxUint64)
+}
+
+func (set *bitset[E]) Floor(elem E) (E, bool) {
+	if elem < 0 {
+		return 0, false
+	}
+	return set.prev(uint64(elem))
+}
+
+func (set *bitset[E]) Ceiling(elem E) (E, bool) {
+	if elem < 0 {
+		return set.next(0)
+	}
+	return set.next(uint64(elem))
+}
+
+func (set *bitset[E]) Lower(elem E) (E, bool) {
+	if elem <= 0 {
+		return 0, false
+	}
+	return set.prev(uint64(elem) - 1)
+}
+
+func (set *bitset[E]) Higher(elem E) (E, bool) {
+	switch {
+	case elem < 0:
+		return set.next(0)
+	case uint64(elem) == math.MaxUint64:
+		return 0, false
+	}
+	return set.next(uint64(elem) + 1)
+}
+
+func (set *bitset[E]) SubSet(lo, hi E, bounds Bounds) Sorted[E] {
+	i, j := boundsIndex(set, lo, hi, bounds)
+	return set.slice(i, j)
+}
+
+func (set *bitset[E]) HeadSet(hi E) Sorted[E] {
+	return set.slice(0, set.lowerBound(hi))
+}
+
+func (set *bitset[E]) TailSet(lo E) Sorted[E] {
+	return set.slice(set.lowerBound(lo), set.Len())
+}
+
+func (set *bitset[E]) CountRange(lo, hi E) int {
+	i, j := boundsIndex(set, lo, hi, IncludeLo)
+	return j - i
+}
+
+func (set *bitset[E]) Rank(elem E) int {
+	return set.lowerBound(elem)
+}
+
+func (set *bitset[E]) At(i int) E {
+	pos, ok := set.selectBit(i)
+	if !ok {
+		panic("sets: index out of range")
+	}
+	return E(pos)
+}
+
+func (set *bitset[E]) Slice(i, j int) Sorted[E] {
+	if i < 0 || j > set.Len() || i > j {
+		panic("sets: slice bounds out of range")
+	}
+	return set.slice(i, j)
+}
+
+// slice returns a new set with the elements at indices [i, j).
+func (set *bitset[E]) slice(i, j int) *bitset[E] {
+	s := &bitset[E]{}
+	if i >= j {
+		return s
+	}
+	lo, _ := set.selectBit(i)
+	hi, _ := set.selectBit(j - 1)
+	s.words = make([]uint64, hi/64+1)
+	copy(s.words[lo/64:], set.words[lo/64:hi/64+1])
+	s.words[lo/64] &^= 1<<(lo%64) - 1            // Clear bits below lo.
+	s.words[hi/64] &= ^uint64(0) >> (63 - hi%64) // Clear bits above hi.
+	s.recount()
+	return s
+}
+
+func (set *bitset[E]) Clone() Set[E] {
+	return set.clone()
+}
+
+func (set *bitset[E]) clone() *bitset[E] {
+	return &bitset[E]{words: slices.Clone(set.words), counts: slices.Clone(set.counts)}
+}
+
+func (set *bitset[E]) search(elem E) (int, bool) {
+	return set.lowerBound(elem), set.Contains(elem)
+}
+
+// lowerBound returns the number of elements less than elem.
+func (set *bitset[E]) lowerBound(elem E) int {
+	if elem < 0 {
+		return 0
+	}
+	return set.countBelow(uint64(elem))
+}
+
+// upperBound returns the number of elements less than or equal to elem.
+func (set *bitset[E]) upperBound(elem E) int {
+	switch {
+	case elem < 0:
+		return 0
+	case uint64(elem) == math.MaxUint64:
+		return set.Len()
+	}
+	return set.countBelow(uint64(elem) + 1)
+}
+
+// countBelow returns the number of bits set below position i.
+func (set *bitset[E]) countBelow(i uint64) int {
+	if i/64 >= uint64(len(set.words)) {
+		return set.Len()
+	}
+	return set.counts.sum(int(i/64)) + bits.OnesCount64(set.words[i/64]&(1<<(i%64)-1))
+}
+
+// selectBit returns the position of the bit set at index i.
+func (set *bitset[E]) selectBit(i int) (uint64, bool) {
+	if i < 0 {
+		return 0, false
+	}
+	k, i := set.counts.search(i)
+	if k == len(set.words) {
+		return 0, false
+	}
+	w := set.words[k]
+	for ; i > 0; i-- {
+		w &= w - 1
+	}
+	return uint64(k*64 + bits.TrailingZeros64(w)), true
+}
+
+// next returns the least element greater than or equal to position i.
+func (set *bitset[E]) next(i uint64) (E, bool) {
+	for k := i / 64; k < uint64(len(set.words)); k++ {
+		w := set.words[k]
+		if k == i/64 {
+			w &^= 1<<(i%64) - 1
+		}
+		if w != 0 {
+			return E(k*64 + uint64(bits.TrailingZeros64(w))), true
+		}
+	}
+	return 0, false
+}
+
+// prev returns the greatest element less than or equal to position i.
+func (set *bitset[E]) prev(i uint64) (E, bool) {
+	k := min(i/64, uint64(len(set.words)))
+	if k < uint64(len(set.words)) {
+		if w := set.words[k] & (^uint64(0) >> (63 - i%64)); w != 0 { // Clear bits above i.
+			return E(k*64 + uint64(63-bits.LeadingZeros64(w))), true
+		}
+	}
+	for k > 0 {
+		k--
+		if w := set.words[k]; w != 0 {
+			return E(k*64 + uint64(63-bits.LeadingZeros64(w))), true
+		}
+	}
+	return 0, false
+}
+
+func (set *bitset[E]) has(i uint64) bool {
+	return i/64 < uint64(len(set.words)) && set.words[i/64]&(1<<(i%64)) != 0
+}
+
+// word returns the word at index i, which is zero if it's out of range.
+func (set *bitset[E]) word(i int) uint64 {
+	if i < len(set.words) {
+		return set.words[i]
+	}
+	return 0
+}
+
+// grow ensures the set has at least n words.
+func (set *bitset[E]) grow(n int) {
+	if n > len(set.words) {
+		set.words = append(set.words, make([]uint64, n-len(set.words))...)
+		for len(set.counts) < n {
+			set.counts.push(0)
+		}
+	}
+}
+
+// trim removes trailing zero words.
+func (set *bitset[E]) trim() {
+	n := len(set.words)
+	for n > 0 && set.words[n-1] == 0 {
+		n--
+	}
+	set.words = set.words[:n]
+	set.counts.truncate(n)
+}
+
+// recount rebuilds the counts after the words are modified in bulk.
+func (set *bitset[E]) recount() {
+	set.counts.reset(len(set.words), func(i int) int { return bits.OnesCount64(set.words[i]) })
+}
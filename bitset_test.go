@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBitSetNegative(t *testing.T) {
+	set := NewBitSet(0, 1, 2)
+	if set.Contains(-1) {
+		t.Errorf("set.Contains(-1); got: true; want: false")
+	}
+	set.Remove(-1)
+	if e, ok := set.Floor(-1); ok {
+		t.Errorf("set.Floor(-1); got: %v, true; want: false", e)
+	}
+	if e, ok := set.Ceiling(-1); e != 0 || !ok {
+		t.Errorf("set.Ceiling(-1); got: %v, %v; want: 0, true", e, ok)
+	}
+	if e, ok := set.Lower(0); ok {
+		t.Errorf("set.Lower(0); got: %v, true; want: false", e)
+	}
+	if got := set.Rank(-5); got != 0 {
+		t.Errorf("set.Rank(-5); got: %v; want: 0", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("set.Insert(-1); want panic")
+		}
+	}()
+	set.Insert(-1)
+}
+
+func TestBitSetLimits(t *testing.T) {
+	set := NewBitSet[uint8](0, 63, 64, 254, 255)
+	if got, want := set.Elems(), []uint8{0, 63, 64, 254, 255}; !slices.Equal(got, want) {
+		t.Errorf("set.Elems(); got: %v; want: %v", got, want)
+	}
+	if e, ok := set.Higher(math.MaxUint8); ok {
+		t.Errorf("set.Higher(255); got: %v, true; want: false", e)
+	}
+	if e, ok := set.Max(); e != 255 || !ok {
+		t.Errorf("set.Max(); got: %v, %v; want: 255, true", e, ok)
+	}
+	if got, want := set.SubSet(63, 255, IncludeLo).Elems(), []uint8{63, 64, 254}; !slices.Equal(got, want) {
+		t.Errorf("set.SubSet(63, 255, IncludeLo).Elems(); got: %v; want: %v", got, want)
+	}
+	if got, want := slices.Collect(set.Backward()), []uint8{255, 254, 64, 63, 0}; !slices.Equal(got, want) {
+		t.Errorf("set.Backward(); got: %v; want: %v", got, want)
+	}
+}
+
+func TestBitSetWords(t *testing.T) {
+	small := NewBitSet(1, 2, 3)
+	large := NewBitSet(2, 3, 500, 1000)
+	for _, tt := range []struct {
+		name string
+		set  Set[int]
+		want []int
+	}{
+		{"small.Intersection(large)", small.Intersection(large), []int{2, 3}},
+		{"large.Intersection(small)", large.Intersection(small), []int{2, 3}},
+		{"small.Union(large)", small.Union(large), []int{1, 2, 3, 500, 1000}},
+		{"small.Difference(large)", small.Difference(large), []int{1}},
+		{"large.Difference(small)", large.Difference(small), []int{500, 1000}},
+		{"small.SymmetricDifference(large)", small.SymmetricDifference(large), []int{1, 500, 1000}},
+	} {
+		if got := tt.set.Elems(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s.Elems(); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+	// Trailing empty words are trimmed.
+	large.RemoveAll(500, 1000)
+	if got, want := len(large.(*bitset[int]).words), 1; got != want {
+		t.Errorf("len(large.words); got: %v; want: %v", got, want)
+	}
+	if !small.ContainsSet(large) || large.ContainsSet(small) {
+		t.Errorf("small.ContainsSet(large) or large.ContainsSet(small); unexpected result")
+	}
+}
+
+func TestBitSetRandom(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed: %v", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	randElems := func(n int) []int {
+		elems := make([]int, n)
+		for i := range elems {
+			elems[i] = rnd.Intn(2000)
+		}
+		return elems
+	}
+
+	set, want := NewBitSet[int](), NewSorted[int]()
+	for i := range 200 {
+		elems := randElems(rnd.Intn(200))
+		other := NewBitSet(elems...)
+		switch i % 6 {
+		case 0:
+			set.InsertSet(other)
+			want.InsertAll(elems...)
+		case 1:
+			set.RemoveSet(other)
+			want.RemoveAll(elems...)
+		case 2:
+			set = set.SymmetricDifference(other).(Sorted[int])
+			want = want.SymmetricDifference(NewSorted(elems...)).(Sorted[int])
+		case 3:
+			lo, hi := rnd.Intn(2000), rnd.Intn(2000)
+			set = set.Union(other.SubSet(lo, hi, Closed)).(Sorted[int])
+			want.InsertSet(NewSorted(elems...).SubSet(lo, hi, Closed))
+		case 4:
+			for _, e := range elems {
+				set.Insert(e)
+				want.Insert(e)
+				e = rnd.Intn(2000)
+				set.Remove(e)
+				want.Remove(e)
+			}
+		case 5:
+			elems = randElems(1500)
+			set = set.Intersection(NewBitSet(elems...)).(Sorted[int])
+			want = want.Intersection(NewSorted(elems...)).(Sorted[int])
+		}
+		if !slices.Equal(set.Elems(), want.Elems()) {
+			t.Fatalf("step %d: unexpected elements", i)
+		}
+		if got, want := set.Len(), want.Len(); got != want {
+			t.Fatalf("step %d: set.Len(); got: %v; want: %v", i, got, want)
+		}
+		if n := want.Len(); n > 0 {
+			k := rnd.Intn(n)
+			if got, want := set.At(k), want.At(k); got != want {
+				t.Fatalf("step %d: set.At(%d); got: %v; want: %v", i, k, got, want)
+			}
+		}
+		lo, hi := rnd.Intn(2100), rnd.Intn(2100)
+		if got, want := set.Rank(lo), want.Rank(lo); got != want {
+			t.Fatalf("step %d: set.Rank(%v); got: %v; want: %v", i, lo, got, want)
+		}
+		if got, want := set.CountRange(lo, hi), want.CountRange(lo, hi); got != want {
+			t.Fatalf("step %d: set.CountRange(%v, %v); got: %v; want: %v", i, lo, hi, got, want)
+		}
+	}
+}
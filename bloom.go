@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// ErrIncompatibleFilters is returned when combining filters with different parameters.
+var ErrIncompatibleFilters = errors.New("sets: incompatible filters")
+
+var errInvalidBloom = errors.New("sets: invalid bloom filter encoding")
+
+// bloomVersion is the version of the binary encoding of a bloom filter.
+const bloomVersion = 1
+
+// A Bloom is a bloom filter, which is a space-efficient approximate set.
+// It may report that it contains an element that was never inserted, but it never
+// reports that it doesn't contain an element that was inserted. Elements can't be removed.
+//
+// The zero value is not usable. Use NewBloom to create a bloom filter.
+type Bloom[E any] struct {
+	words []uint64
+	k     int // Number of hash functions.
+	hash  func(E) uint64
+}
+
+// NewBloom returns a bloom filter sized to hold n elements with the given
+// false positive rate, which must be between zero and one exclusive.
+// The hash function must distribute elements uniformly over all 64 bits.
+// Filters may only be combined or decoded if they use the same hash function.
+func NewBloom[E any](n int, fpRate float64, hash func(E) uint64) *Bloom[E] {
+	if !(fpRate > 0 && fpRate < 1) {
+		panic("sets: bloom filter false positive rate out of range")
+	}
+	n = max(n, 1)
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	words := max(int(math.Ceil(m/64)), 1)
+	k := max(int(math.Round(float64(words*64)/float64(n)*math.Ln2)), 1)
+	return &Bloom[E]{
+		words: make([]uint64, words),
+		k:     k,
+		hash:  hash,
+	}
+}
+
+// Contains returns a value indicating if the given element may be in the filter.
+func (f *Bloom[E]) Contains(elem E) bool {
+	h1, h2 := f.hashes(elem)
+	m := f.bits()
+	for i := range uint64(f.k) {
+		if pos := (h1 + i*h2) % m; f.words[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAll returns a value indicating if all the given elements may be in the filter.
+func (f *Bloom[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if !f.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Insert adds the given element to the filter.
+func (f *Bloom[E]) Insert(elem E) {
+	h1, h2 := f.hashes(elem)
+	m := f.bits()
+	for i := range uint64(f.k) {
+		pos := (h1 + i*h2) % m
+		f.words[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// InsertAll adds the given elements to the filter.
+func (f *Bloom[E]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		f.Insert(e)
+	}
+}
+
+// InsertSet adds the elements of the given set to the filter.
+func (f *Bloom[E]) InsertSet(set Set[E]) {
+	set.Range(func(e E) bool {
+		f.Insert(e)
+		return true
+	})
+}
+
+// Union returns a new filter that contains the elements of the filter and other.
+// It returns ErrIncompatibleFilters if the filters have different sizes or
+// numbers of hash functions.
+func (f *Bloom[E]) Union(other *Bloom[E]) (*Bloom[E], error) {
+	if len(f.words) != len(other.words) || f.k != other.k {
+		return nil, ErrIncompatibleFilters
+	}
+	u := f.Clone()
+	for i, w := range other.words {
+		u.words[i] |= w
+	}
+	return u, nil
+}
+
+// FalsePositiveRate returns an estimate of the probability that Contains
+// reports true for an element that was never inserted, based on the fraction
+// of bits that are set.
+func (f *Bloom[E]) FalsePositiveRate() float64 {
+	n := 0
+	for _, w := range f.words {
+		n += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(n)/float64(f.bits()), float64(f.k))
+}
+
+// Clone returns a copy of the filter.
+func (f *Bloom[E]) Clone() *Bloom[E] {
+	return &Bloom[E]{
+		words: slices.Clone(f.words),
+		k:     f.k,
+		hash:  f.hash,
+	}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f *Bloom[E]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 1+2*binary.MaxVarintLen64+8*len(f.words))
+	b = append(b, bloomVersion)
+	b = binary.AppendUvarint(b, uint64(f.k))
+	b = binary.AppendUvarint(b, uint64(len(f.words)))
+	for _, w := range f.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It replaces the contents and parameters of the filter, but retains its hash function,
+// which must be the same as the one used by the encoded filter.
+func (f *Bloom[E]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != bloomVersion {
+		return errInvalidBloom
+	}
+	data = data[1:]
+	k, n := binary.Uvarint(data)
+	if n <= 0 || k == 0 || k > math.MaxInt32 {
+		return errInvalidBloom
+	}
+	data = data[n:]
+	words, n := binary.Uvarint(data)
+	if n <= 0 || words == 0 || words != uint64(len(data)-n)/8 || (len(data)-n)%8 != 0 {
+		return errInvalidBloom
+	}
+	data = data[n:]
+	f.k = int(k)
+	f.words = make([]uint64, words)
+	for i := range f.words {
+		f.words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
+
+func (f *Bloom[E]) bits() uint64 {
+	return uint64(len(f.words)) * 64
+}
+
+// hashes returns the two hashes from which the filter's k hashes are derived.
+func (f *Bloom[E]) hashes(elem E) (uint64, uint64) {
+	h := f.hash(elem)
+	return h, mix64(h) | 1 // Never zero, so the k positions aren't all the same.
+}
+
+// mix64 returns a well-distributed hash derived from h.
+// It's the finalizer of SplitMix64.
+func mix64(h uint64) uint64 {
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"errors"
+	"hash/maphash"
+	"testing"
+)
+
+var (
+	_ Membership[int] = New[int]()
+	_ Membership[int] = (*Bloom[int])(nil)
+)
+
+var testSeed = maphash.MakeSeed()
+
+func hashInt(v int) uint64 {
+	return hashComparable(testSeed, v)
+}
+
+func TestBloom(t *testing.T) {
+	const n = 10000
+	const fpRate = 0.01
+
+	set := New[int]()
+	for i := range n {
+		set.Insert(i)
+	}
+	f := NewBloom(n, fpRate, hashInt)
+	f.InsertSet(set)
+	for i := range n {
+		if !f.Contains(i) {
+			t.Fatalf("f.Contains(%d); got: false; want: true", i)
+		}
+	}
+	if !f.ContainsAll(1, 2, 3) {
+		t.Errorf("f.ContainsAll(1, 2, 3); got: false; want: true")
+	}
+
+	fp := 0
+	for i := n; i < 2*n; i++ {
+		if f.Contains(i) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / n; rate > 2*fpRate {
+		t.Errorf("false positive rate; got: %v; want: <= %v", rate, 2*fpRate)
+	}
+	if rate := f.FalsePositiveRate(); rate <= 0 || rate > 2*fpRate {
+		t.Errorf("f.FalsePositiveRate(); got: %v; want: (0, %v]", rate, 2*fpRate)
+	}
+	if rate := NewBloom(n, fpRate, hashInt).FalsePositiveRate(); rate != 0 {
+		t.Errorf("empty.FalsePositiveRate(); got: %v; want: 0", rate)
+	}
+}
+
+func TestBloomUnion(t *testing.T) {
+	a := NewBloom(100, 0.01, hashInt)
+	a.InsertAll(1, 2, 3)
+	b := NewBloom(100, 0.01, hashInt)
+	b.InsertAll(4, 5, 6)
+
+	u, err := a.Union(b)
+	if err != nil {
+		t.Fatalf("a.Union(b); unexpected error: %v", err)
+	}
+	if !u.ContainsAll(1, 2, 3, 4, 5, 6) {
+		t.Errorf("u.ContainsAll(1, 2, 3, 4, 5, 6); got: false; want: true")
+	}
+	if a.Contains(4) && a.Contains(5) && a.Contains(6) {
+		t.Errorf("a was modified by Union")
+	}
+
+	c := NewBloom(1000, 0.01, hashInt)
+	if _, err := a.Union(c); !errors.Is(err, ErrIncompatibleFilters) {
+		t.Errorf("a.Union(c); got error: %v; want: %v", err, ErrIncompatibleFilters)
+	}
+}
+
+func TestBloomBinary(t *testing.T) {
+	f := NewBloom(1000, 0.001, hashInt)
+	for i := range 1000 {
+		f.Insert(i * 7)
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("f.MarshalBinary(); unexpected error: %v", err)
+	}
+
+	g := NewBloom(1, 0.5, hashInt)
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("g.UnmarshalBinary(); unexpected error: %v", err)
+	}
+	for i := range 1000 {
+		if !g.Contains(i * 7) {
+			t.Fatalf("g.Contains(%d); got: false; want: true", i*7)
+		}
+	}
+	if _, err := f.Union(g); err != nil {
+		t.Errorf("f.Union(g); unexpected error: %v", err)
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		{0},
+		data[:len(data)-1],
+		append(data[:len(data):len(data)], 0),
+	} {
+		if err := g.UnmarshalBinary(bad); err == nil {
+			t.Errorf("g.UnmarshalBinary(%d bytes); expected error", len(bad))
+		}
+	}
+}
+
+func TestBloomInvalid(t *testing.T) {
+	for _, fpRate := range []float64{0, 1, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewBloom(1, %v, hash); want panic", fpRate)
+				}
+			}()
+			NewBloom(1, fpRate, hashInt)
+		}()
+	}
+}
@@ -0,0 +1,741 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sort"
+)
+
+const (
+	btreeDegree      = 32
+	btreeMaxElems    = 2*btreeDegree - 1
+	btreeMinElems    = btreeDegree - 1
+	btreeMaxChildren = 2 * btreeDegree
+)
+
+// NewSortedBTree returns a sorted set initialized with the given elements.
+// It's backed by a B-tree, so inserting or removing a single element
+// takes O(log n) time instead of the O(n) time required by NewSorted.
+func NewSortedBTree[E cmp.Ordered](elems ...E) Sorted[E] {
+	return NewSortedBTreeCmpEqFunc(cmp.Compare[E], equal[E], elems...)
+}
+
+// NewSortedBTreeCmpFunc returns a sorted set initialized with the given elements.
+// The comparison function is used to order and identify elements.
+// It's backed by a B-tree, so inserting or removing a single element
+// takes O(log n) time instead of the O(n) time required by NewSortedCmpFunc.
+func NewSortedBTreeCmpFunc[E any](cmp CmpFunc[E], elems ...E) Sorted[E] {
+	return NewSortedBTreeCmpEqFunc(cmp, func(a, b E) bool { return cmp(a, b) == 0 }, elems...)
+}
+
+// NewSortedBTreeCmpEqFunc returns a sorted set initialized with the given elements.
+// The comparison function is only used to order elements and the equality
+// function is used to identify elements.
+// It's backed by a B-tree, so inserting or removing a single element
+// takes O(log n) time instead of the O(n) time required by NewSortedCmpEqFunc.
+//
+// It has the same semantics as NewSortedCmpEqFunc for unique elements
+// for which cmp(a, b) == 0 and eq(a, b) == false.
+func NewSortedBTreeCmpEqFunc[E any](cmp CmpFunc[E], eq EqFunc[E], elems ...E) Sorted[E] {
+	return newBTree(stableSortUniqCmpEq(slices.Clone(elems), cmp, eq), cmp, eq)
+}
+
+// newBTree returns a B-tree built from the given elements,
+// which must be sorted and unique.
+func newBTree[E any](elems []E, cmp CmpFunc[E], eq EqFunc[E]) *btree[E] {
+	set := &btree[E]{cmp: cmp, eq: eq}
+	if n := len(elems); n > 0 {
+		height := 1
+		for capacity := btreeMaxElems; capacity < n; capacity = capacity*btreeMaxChildren + btreeMaxElems {
+			height++
+		}
+		set.root = buildBNode(elems, height)
+	}
+	return set
+}
+
+type btree[E any] struct {
+	root *bnode[E]
+	cmp  CmpFunc[E]
+	eq   EqFunc[E]
+}
+
+func (set *btree[E]) Contains(elem E) bool {
+	_, ok := set.search(elem)
+	return ok
+}
+
+func (set *btree[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if _, ok := set.search(e); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *btree[E]) ContainsSet(other Set[E]) bool {
+	// Merging takes O(n+m) time, while searching for each element of other
+	// takes O(m*log(n)) time, so only merge when other is relatively large.
+	if other.Len() > set.Len()/btreeDegree {
+		if b, ok := sortedSeq(other, set.cmp); ok {
+			return set.containsSeq(b)
+		}
+	}
+	ok := true
+	other.Range(func(e E) bool {
+		_, ok = set.search(e)
+		return ok
+	})
+	return ok
+}
+
+// containsSeq returns a value indicating if the set contains all the elements
+// of the sequence, which must be strictly increasing by the set's comparison function.
+func (set *btree[E]) containsSeq(seq iter.Seq[E]) bool {
+	next, stop := iter.Pull(set.All())
+	defer stop()
+	a, ok := next()
+	for b := range seq {
+		for ok && set.cmp(a, b) < 0 {
+			a, ok = next()
+		}
+		found := false
+		for ok && set.cmp(a, b) == 0 {
+			found = found || set.eq(a, b)
+			a, ok = next()
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *btree[E]) Insert(elem E) {
+	idx, found := set.search(elem)
+	if found {
+		set.root.setAt(idx, elem)
+		return
+	}
+	if set.root == nil {
+		set.root = &bnode[E]{elems: []E{elem}, size: 1}
+		return
+	}
+	if len(set.root.elems) == btreeMaxElems {
+		set.root = &bnode[E]{children: []*bnode[E]{set.root}, size: set.root.size}
+		set.root.split(0)
+	}
+	set.root.insertAt(idx, elem)
+}
+
+func (set *btree[E]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		set.Insert(e)
+	}
+}
+
+func (set *btree[E]) InsertSet(other Set[E]) {
+	if set == other {
+		return
+	}
+	// Rebuilding the tree takes O(n+m*log(m)) time, or O(n+m) time if other is already
+	// sorted, while inserting each element takes O(m*log(n+m)) time, so only rebuild
+	// when other is relatively large.
+	if other.Len() > set.Len()/btreeDegree {
+		elems := stableSortUniqCmpEq(other.Elems(), set.cmp, set.eq)
+		elems = mergeSortedLists(set.Elems(), elems, set.cmp, set.eq)
+		set.root = newBTree(elems, set.cmp, set.eq).root
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Insert(e)
+		return true
+	})
+}
+
+func (set *btree[E]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set.Insert(e)
+	}
+}
+
+func (set *btree[E]) Remove(elem E) {
+	idx, found := set.search(elem)
+	if !found {
+		return
+	}
+	set.root.removeAt(idx)
+	if len(set.root.elems) == 0 {
+		if set.root.leaf() {
+			set.root = nil
+		} else {
+			set.root = set.root.children[0]
+		}
+	}
+}
+
+func (set *btree[E]) RemoveAll(elems ...E) {
+	for _, e := range elems {
+		set.Remove(e)
+	}
+}
+
+func (set *btree[E]) RemoveSet(other Set[E]) {
+	if set == other {
+		set.root = nil
+		return
+	}
+	// Like InsertSet, only rebuild the tree when other is relatively large.
+	if other.Len() > set.Len()/btreeDegree {
+		if b, ok := sortedSeq(other, set.cmp); ok {
+			set.root = newBTree(diffSortedSeq(set.Elems(), b, set.cmp, set.eq), set.cmp, set.eq).root
+			return
+		}
+	}
+	other.Range(func(e E) bool {
+		set.Remove(e)
+		return true
+	})
+}
+
+func (set *btree[E]) Intersection(other Set[E]) Set[E] {
+	if b, ok := set.mergeable(other); ok {
+		return newBTree(intersectSortedSeq(set.Elems(), b, set.cmp, set.eq), set.cmp, set.eq)
+	}
+	var elems []E
+	for e := range set.All() {
+		if other.Contains(e) {
+			elems = append(elems, e)
+		}
+	}
+	return newBTree(elems, set.cmp, set.eq)
+}
+
+func (set *btree[E]) Union(other Set[E]) Set[E] {
+	elems := stableSortUniqCmpEq(other.Elems(), set.cmp, set.eq)
+	elems = mergeSortedLists(set.Elems(), elems, set.cmp, set.eq)
+	return newBTree(elems, set.cmp, set.eq)
+}
+
+func (set *btree[E]) Difference(other Set[E]) Set[E] {
+	if b, ok := set.mergeable(other); ok {
+		return newBTree(diffSortedSeq(set.Elems(), b, set.cmp, set.eq), set.cmp, set.eq)
+	}
+	var elems []E
+	for e := range set.All() {
+		if !other.Contains(e) {
+			elems = append(elems, e)
+		}
+	}
+	return newBTree(elems, set.cmp, set.eq)
+}
+
+func (set *btree[E]) SymmetricDifference(other Set[E]) Set[E] {
+	if b, ok := sortedSeq(other, set.cmp); ok {
+		return newBTree(symDiffSortedSeq(set.Elems(), b, set.cmp, set.eq), set.cmp, set.eq)
+	}
+	var a, b []E
+	for e := range set.All() {
+		if !other.Contains(e) {
+			a = append(a, e)
+		}
+	}
+	other.Range(func(e E) bool {
+		if !set.Contains(e) {
+			b = append(b, e)
+		}
+		return true
+	})
+	b = stableSortUniqCmpEq(b, set.cmp, set.eq)
+	return newBTree(mergeSortedLists(a, b, set.cmp, set.eq), set.cmp, set.eq)
+}
+
+// mergeable returns an iterator over the elements of the other set and true if they're
+// sorted and unique by the set's comparison function and merging them with the set's
+// elements is faster than searching for each of the set's elements.
+func (set *btree[E]) mergeable(other Set[E]) (iter.Seq[E], bool) {
+	// Merging takes O(n+m) time, while searching a sorted set for each element
+	// takes O(n*log(m)) time, so only merge when other is relatively small.
+	if other.Len() > set.Len()*btreeDegree {
+		return nil, false
+	}
+	return sortedSeq(other, set.cmp)
+}
+
+func (set *btree[E]) Len() int {
+	if set.root == nil {
+		return 0
+	}
+	return set.root.size
+}
+
+func (set *btree[E]) Elems() []E {
+	return set.appendRange(nil, 0, set.Len())
+}
+
+func (set *btree[E]) Range(fn func(v E) bool) {
+	if set.root != nil {
+		set.root.ascend(fn)
+	}
+}
+
+func (set *btree[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if set.root != nil {
+			set.root.ascend(yield)
+		}
+	}
+}
+
+func (set *btree[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if set.root != nil {
+			set.root.descend(yield)
+		}
+	}
+}
+
+func (set *btree[E]) Min() (E, bool) {
+	return set.index(0)
+}
+
+func (set *btree[E]) Max() (E, bool) {
+	return set.index(set.Len() - 1)
+}
+
+func (set *btree[E]) Floor(elem E) (E, bool) {
+	idx, found := set.search(elem)
+	if found {
+		return set.root.at(idx), true
+	}
+	return set.index(idx - 1) // Last element of the run.
+}
+
+func (set *btree[E]) Ceiling(elem E) (E, bool) {
+	if idx, found := set.search(elem); found {
+		return set.root.at(idx), true
+	}
+	return set.index(set.lowerBound(elem)) // First element of the run.
+}
+
+func (set *btree[E]) Lower(elem E) (E, bool) {
+	return set.index(set.lowerBound(elem) - 1)
+}
+
+func (set *btree[E]) Higher(elem E) (E, bool) {
+	return set.index(set.upperBound(elem))
+}
+
+func (set *btree[E]) SubSet(lo, hi E, bounds Bounds) Sorted[E] {
+	i, j := boundsIndex(set, lo, hi, bounds)
+	return set.slice(i, j)
+}
+
+func (set *btree[E]) HeadSet(hi E) Sorted[E] {
+	return set.slice(0, set.lowerBound(hi))
+}
+
+func (set *btree[E]) TailSet(lo E) Sorted[E] {
+	return set.slice(set.lowerBound(lo), set.Len())
+}
+
+func (set *btree[E]) CountRange(lo, hi E) int {
+	i, j := boundsIndex(set, lo, hi, IncludeLo)
+	return j - i
+}
+
+func (set *btree[E]) Rank(elem E) int {
+	idx, _ := set.search(elem)
+	return idx
+}
+
+func (set *btree[E]) At(i int) E {
+	if i < 0 || i >= set.Len() {
+		panic("sets: index out of range")
+	}
+	return set.root.at(i)
+}
+
+func (set *btree[E]) Slice(i, j int) Sorted[E] {
+	if i < 0 || j > set.Len() || i > j {
+		panic("sets: slice bounds out of range")
+	}
+	return set.slice(i, j)
+}
+
+func (set *btree[E]) slice(i, j int) *btree[E] {
+	return newBTree(set.appendRange(nil, i, j), set.cmp, set.eq)
+}
+
+func (set *btree[E]) Clone() Set[E] {
+	s := &btree[E]{cmp: set.cmp, eq: set.eq}
+	if set.root != nil {
+		s.root = set.root.clone()
+	}
+	return s
+}
+
+// appendRange appends the elements at indices [i, j) to dst.
+func (set *btree[E]) appendRange(dst []E, i, j int) []E {
+	if i >= j {
+		return dst
+	}
+	return set.root.appendRange(slices.Grow(dst, j-i), i, j)
+}
+
+// index returns the element at index i and true if i is in range.
+// Otherwise, it returns the zero value and false.
+func (set *btree[E]) index(i int) (E, bool) {
+	if i < 0 || i >= set.Len() {
+		var zero E
+		return zero, false
+	}
+	return set.root.at(i), true
+}
+
+func (set *btree[E]) search(elem E) (idx int, found bool) {
+	n := set.Len()
+	// There are two options:
+	// 	1. The sort key doesn't exist and idx is where it should be inserted.
+	//	2. The sort key exists one or more times and idx is where it first appears.
+	//
+	// Iterate through elements as long as the sort key matches,
+	// looking for a fully matching value.
+	for idx = set.lowerBound(elem); idx < n; idx++ {
+		e := set.root.at(idx)
+		if set.cmp(elem, e) != 0 {
+			break
+		}
+		if set.eq(e, elem) {
+			return idx, true
+		}
+	}
+	return idx, false
+}
+
+// lowerBound returns the index of the first element for which cmp(elem, e) <= 0.
+func (set *btree[E]) lowerBound(elem E) int {
+	return set.bound(func(e E) bool { return set.cmp(elem, e) <= 0 })
+}
+
+// upperBound returns the index of the first element for which cmp(elem, e) < 0.
+func (set *btree[E]) upperBound(elem E) int {
+	return set.bound(func(e E) bool { return set.cmp(elem, e) < 0 })
+}
+
+// bound returns the index of the first element for which fn returns true,
+// where fn must return false for a prefix of the elements and true for the rest.
+func (set *btree[E]) bound(fn func(E) bool) int {
+	idx := 0
+	for n := set.root; n != nil; {
+		i := sort.Search(len(n.elems), func(i int) bool { return fn(n.elems[i]) })
+		idx += i
+		if n.leaf() {
+			break
+		}
+		for _, child := range n.children[:i] {
+			idx += child.size
+		}
+		n = n.children[i]
+	}
+	return idx
+}
+
+// A bnode is a node of a B-tree in which the elements are addressed by index.
+// Every node except the root holds between btreeMinElems and btreeMaxElems elements.
+// An internal node with k elements has k+1 children.
+type bnode[E any] struct {
+	elems    []E
+	children []*bnode[E] // Nil for leaves.
+	size     int         // Number of elements in the subtree.
+}
+
+// buildBNode returns a subtree of the given height built from the given elements.
+func buildBNode[E any](elems []E, height int) *bnode[E] {
+	n := &bnode[E]{size: len(elems)}
+	if height == 1 {
+		n.elems = slices.Clone(elems)
+		return n
+	}
+	// Distribute the elements evenly among as few children as possible.
+	capacity := btreeMaxElems
+	for h := 2; h < height; h++ {
+		capacity = capacity*btreeMaxChildren + btreeMaxElems
+	}
+	k := (len(elems) + capacity + 1) / (capacity + 1) // ceil((len+1) / (capacity+1))
+	rem := len(elems) - (k - 1)                       // Elements in children.
+	n.elems = make([]E, 0, k-1)
+	n.children = make([]*bnode[E], 0, k)
+	for c := range k {
+		size := rem / k
+		if c < rem%k {
+			size++
+		}
+		n.children = append(n.children, buildBNode(elems[:size], height-1))
+		elems = elems[size:]
+		if c < k-1 {
+			n.elems = append(n.elems, elems[0])
+			elems = elems[1:]
+		}
+	}
+	return n
+}
+
+func (n *bnode[E]) leaf() bool {
+	return n.children == nil
+}
+
+// at returns the element at index i of the subtree.
+func (n *bnode[E]) at(i int) E {
+	for !n.leaf() {
+		for c, child := range n.children {
+			if i < child.size {
+				n = child
+				break
+			}
+			if i -= child.size; i == 0 {
+				return n.elems[c]
+			}
+			i--
+		}
+	}
+	return n.elems[i]
+}
+
+// setAt overwrites the element at index i of the subtree.
+func (n *bnode[E]) setAt(i int, elem E) {
+	for !n.leaf() {
+		for c, child := range n.children {
+			if i < child.size {
+				n = child
+				break
+			}
+			if i -= child.size; i == 0 {
+				n.elems[c] = elem
+				return
+			}
+			i--
+		}
+	}
+	n.elems[i] = elem
+}
+
+// insertAt inserts the element at index i of the subtree, which must not be full.
+func (n *bnode[E]) insertAt(i int, elem E) {
+	n.size++
+	if n.leaf() {
+		n.elems = slices.Insert(n.elems, i, elem)
+		return
+	}
+	for c := 0; ; c++ {
+		child := n.children[c]
+		if i > child.size {
+			i -= child.size + 1
+			continue
+		}
+		if len(child.elems) == btreeMaxElems {
+			n.split(c)
+			if left := n.children[c]; i > left.size {
+				i -= left.size + 1
+				c++
+			}
+		}
+		n.children[c].insertAt(i, elem)
+		return
+	}
+}
+
+// split splits the full child at index c into two children
+// separated by its median element.
+func (n *bnode[E]) split(c int) {
+	left := n.children[c]
+	mid := len(left.elems) / 2
+	median := left.elems[mid]
+	right := &bnode[E]{elems: slices.Clone(left.elems[mid+1:])}
+	clear(left.elems[mid:]) // Zero out moved elements to prevent leaks.
+	left.elems = left.elems[:mid]
+	right.size = len(right.elems)
+	if !left.leaf() {
+		right.children = slices.Clone(left.children[mid+1:])
+		clear(left.children[mid+1:])
+		left.children = left.children[:mid+1]
+		for _, child := range right.children {
+			right.size += child.size
+		}
+	}
+	left.size -= right.size + 1
+	n.elems = slices.Insert(n.elems, c, median)
+	n.children = slices.Insert(n.children, c+1, right)
+}
+
+// removeAt removes and returns the element at index i of the subtree.
+// It may leave the node with fewer than btreeMinElems elements.
+func (n *bnode[E]) removeAt(i int) E {
+	n.size--
+	if n.leaf() {
+		elem := n.elems[i]
+		n.elems = slices.Delete(n.elems, i, i+1)
+		return elem
+	}
+	for c, child := range n.children {
+		switch {
+		case i < child.size:
+			elem := child.removeAt(i)
+			n.rebalance(c)
+			return elem
+		case i == child.size:
+			// Replace the separator with its predecessor.
+			elem := n.elems[c]
+			n.elems[c] = child.removeAt(child.size - 1)
+			n.rebalance(c)
+			return elem
+		}
+		i -= child.size + 1
+	}
+	panic("unreachable")
+}
+
+// rebalance restores the minimum number of elements in the child at index c
+// by borrowing from or merging with one of its siblings.
+func (n *bnode[E]) rebalance(c int) {
+	child := n.children[c]
+	if len(child.elems) >= btreeMinElems {
+		return
+	}
+	switch {
+	case c > 0 && len(n.children[c-1].elems) > btreeMinElems:
+		// Rotate right from the left sibling.
+		left := n.children[c-1]
+		moved := 1
+		child.elems = slices.Insert(child.elems, 0, n.elems[c-1])
+		n.elems[c-1] = left.elems[len(left.elems)-1]
+		left.elems = slices.Delete(left.elems, len(left.elems)-1, len(left.elems))
+		if !left.leaf() {
+			last := left.children[len(left.children)-1]
+			child.children = slices.Insert(child.children, 0, last)
+			left.children = slices.Delete(left.children, len(left.children)-1, len(left.children))
+			moved += last.size
+		}
+		left.size -= moved
+		child.size += moved
+	case c < len(n.elems) && len(n.children[c+1].elems) > btreeMinElems:
+		// Rotate left from the right sibling.
+		right := n.children[c+1]
+		moved := 1
+		child.elems = append(child.elems, n.elems[c])
+		n.elems[c] = right.elems[0]
+		right.elems = slices.Delete(right.elems, 0, 1)
+		if !right.leaf() {
+			first := right.children[0]
+			child.children = append(child.children, first)
+			right.children = slices.Delete(right.children, 0, 1)
+			moved += first.size
+		}
+		right.size -= moved
+		child.size += moved
+	case c > 0:
+		n.merge(c - 1)
+	default:
+		n.merge(c)
+	}
+}
+
+// merge merges the child at index c+1 and the separator at index c
+// into the child at index c.
+func (n *bnode[E]) merge(c int) {
+	left, right := n.children[c], n.children[c+1]
+	left.elems = append(left.elems, n.elems[c])
+	left.elems = append(left.elems, right.elems...)
+	left.children = append(left.children, right.children...)
+	left.size += 1 + right.size
+	n.elems = slices.Delete(n.elems, c, c+1)
+	n.children = slices.Delete(n.children, c+1, c+2)
+}
+
+// appendRange appends the elements at indices [i, j) of the subtree to dst.
+func (n *bnode[E]) appendRange(dst []E, i, j int) []E {
+	if n.leaf() {
+		return append(dst, n.elems[i:j]...)
+	}
+	off := 0
+	for c, child := range n.children {
+		if off >= j {
+			break
+		}
+		end := off + child.size // Index of the separator following the child.
+		if i < end {
+			dst = child.appendRange(dst, max(i-off, 0), min(j-off, child.size))
+		}
+		if c < len(n.elems) && i <= end && end < j {
+			dst = append(dst, n.elems[c])
+		}
+		off = end + 1
+	}
+	return dst
+}
+
+// ascend calls fn with each element of the subtree in sorted order
+// until there are no elements remaining or fn returns false.
+func (n *bnode[E]) ascend(fn func(E) bool) bool {
+	if n.leaf() {
+		for _, e := range n.elems {
+			if !fn(e) {
+				return false
+			}
+		}
+		return true
+	}
+	for c, child := range n.children {
+		if !child.ascend(fn) {
+			return false
+		}
+		if c < len(n.elems) && !fn(n.elems[c]) {
+			return false
+		}
+	}
+	return true
+}
+
+// descend calls fn with each element of the subtree in reverse sorted order
+// until there are no elements remaining or fn returns false.
+func (n *bnode[E]) descend(fn func(E) bool) bool {
+	if n.leaf() {
+		for i := len(n.elems) - 1; i >= 0; i-- {
+			if !fn(n.elems[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	for c := len(n.children) - 1; c >= 0; c-- {
+		if c < len(n.elems) && !fn(n.elems[c]) {
+			return false
+		}
+		if !n.children[c].descend(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *bnode[E]) clone() *bnode[E] {
+	c := &bnode[E]{
+		elems: slices.Clone(n.elems),
+		size:  n.size,
+	}
+	if !n.leaf() {
+		c.children = make([]*bnode[E], len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+	return c
+}
@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBTreeBuild(t *testing.T) {
+	for _, n := range []int{0, 1, btreeMaxElems, btreeMaxElems + 1, 1000, 5000, 100000} {
+		elems := make([]int, n)
+		for i := range elems {
+			elems[i] = i
+		}
+		set := newBTree(elems, cmp.Compare[int], equal[int])
+		checkBTree(t, set)
+		if got := set.Elems(); !slices.Equal(got, elems) {
+			t.Fatalf("newBTree(%v).Elems(); unexpected elements", n)
+		}
+	}
+}
+
+func TestBTreeRandom(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed: %v", seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	set := NewSortedBTree[int]().(*btree[int])
+	want := NewSorted[int]()
+	for i := 0; i < 20000; i++ {
+		e := rng.Intn(5000)
+		if rng.Intn(3) == 0 {
+			set.Remove(e)
+			want.Remove(e)
+		} else {
+			set.Insert(e)
+			want.Insert(e)
+		}
+		if i%1000 == 0 {
+			checkBTree(t, set)
+		}
+	}
+	checkBTree(t, set)
+	if got, want := set.Elems(), want.Elems(); !slices.Equal(got, want) {
+		t.Fatalf("set.Elems(); got: %v elements; want: %v elements", len(got), len(want))
+	}
+	for i := 0; i < 100; i++ {
+		e := rng.Intn(5000)
+		if got, want := set.Rank(e), want.Rank(e); got != want {
+			t.Fatalf("set.Rank(%v); got: %v; want: %v", e, got, want)
+		}
+		if i < set.Len() {
+			if got, want := set.At(i), want.At(i); got != want {
+				t.Fatalf("set.At(%v); got: %v; want: %v", i, got, want)
+			}
+		}
+		lo, hi := rng.Intn(5000), rng.Intn(5000)
+		if got, want := set.SubSet(lo, hi, Closed).Elems(), want.SubSet(lo, hi, Closed).Elems(); !slices.Equal(got, want) {
+			t.Fatalf("set.SubSet(%v, %v, Closed); got: %v; want: %v", lo, hi, got, want)
+		}
+	}
+	for e := range want.All() {
+		set.Remove(e)
+	}
+	checkBTree(t, set)
+	if got := set.Len(); got != 0 {
+		t.Fatalf("set.Len(); got: %v; want: 0", got)
+	}
+}
+
+func checkBTree[E any](t *testing.T, set *btree[E]) {
+	t.Helper()
+	if set.root == nil {
+		return
+	}
+	leafDepth := -1
+	var check func(n *bnode[E], depth int)
+	check = func(n *bnode[E], depth int) {
+		if n != set.root && len(n.elems) < btreeMinElems {
+			t.Fatalf("node has too few elements: %v", len(n.elems))
+		}
+		if len(n.elems) > btreeMaxElems {
+			t.Fatalf("node has too many elements: %v", len(n.elems))
+		}
+		size := len(n.elems)
+		if n.leaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				t.Fatalf("leaves at different depths: %v and %v", depth, leafDepth)
+			}
+		} else {
+			if len(n.children) != len(n.elems)+1 {
+				t.Fatalf("node has %v children for %v elements", len(n.children), len(n.elems))
+			}
+			for _, child := range n.children {
+				check(child, depth+1)
+				size += child.size
+			}
+		}
+		if n.size != size {
+			t.Fatalf("node size; got: %v; want: %v", n.size, size)
+		}
+	}
+	check(set.root, 0)
+	elems := set.Elems()
+	for i := 1; i < len(elems); i++ {
+		if set.cmp(elems[i-1], elems[i]) > 0 {
+			t.Fatalf("elements out of order at index %v", i)
+		}
+	}
+}
+
+func TestBTreeSortedOps(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var a, b []int
+	for range 2000 {
+		a = append(a, rng.Intn(3000))
+		b = append(b, rng.Intn(3000))
+	}
+	wantA := New(a...)
+	for _, tt := range []struct {
+		name  string
+		other Set[int]
+	}{
+		{"btree", NewSortedBTree(b...)},
+		{"ordered", NewSorted(b...)},
+		{"reversed", NewSortedBTreeCmpFunc(reverseInt, b...)},
+		{"table", New(b...)},
+		{"small", NewSortedBTree(b[:10]...)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := NewSortedBTree(a...).(*btree[int])
+			for _, op := range []struct {
+				name string
+				got  Set[int]
+				want Set[int]
+			}{
+				{"Intersection", set.Intersection(tt.other), wantA.Intersection(tt.other)},
+				{"Union", set.Union(tt.other), wantA.Union(tt.other)},
+				{"Difference", set.Difference(tt.other), wantA.Difference(tt.other)},
+				{"SymmetricDifference", set.SymmetricDifference(tt.other), wantA.SymmetricDifference(tt.other)},
+			} {
+				checkBTree(t, op.got.(*btree[int]))
+				if !Equal(op.got, op.want) {
+					t.Fatalf("set.%s(other); got: %v elements; want: %v elements", op.name, op.got.Len(), op.want.Len())
+				}
+			}
+			if got, want := set.ContainsSet(tt.other), wantA.ContainsSet(tt.other); got != want {
+				t.Fatalf("set.ContainsSet(other); got: %v; want: %v", got, want)
+			}
+			if sub := NewSortedBTree(set.Intersection(tt.other).Elems()...); !set.ContainsSet(sub) {
+				t.Fatalf("set.ContainsSet(set.Intersection(other)); got: false; want: true")
+			}
+			diff := set.Clone().(*btree[int])
+			diff.RemoveSet(tt.other)
+			checkBTree(t, diff)
+			if want := wantA.Difference(tt.other); !Equal[int](diff, want) {
+				t.Fatalf("set.RemoveSet(other); got: %v elements; want: %v elements", diff.Len(), want.Len())
+			}
+			set.InsertSet(tt.other)
+			checkBTree(t, set)
+			if want := wantA.Union(tt.other); !Equal[int](set, want) {
+				t.Fatalf("set.InsertSet(other); got: %v elements; want: %v elements", set.Len(), want.Len())
+			}
+			if got, want := NewSorted(a...).Intersection(tt.other), wantA.Intersection(tt.other); !Equal(got, want) {
+				t.Fatalf("NewSorted(...).Intersection(other); got: %v elements; want: %v elements", got.Len(), want.Len())
+			}
+		})
+	}
+}
+
+func TestBTreeSortedOpsTies(t *testing.T) {
+	type item struct {
+		key, id int
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	eq := func(a, b item) bool { return a == b }
+	a := NewSortedBTreeCmpEqFunc(byKey, eq, item{1, 1}, item{1, 2}, item{2, 1}, item{3, 1})
+	b := NewSortedBTreeCmpEqFunc(byKey, eq, item{0, 1}, item{1, 2}, item{1, 3}, item{3, 1})
+	for _, tt := range []struct {
+		name string
+		got  Set[item]
+		want []item
+	}{
+		{"Intersection", a.Intersection(b), []item{{1, 2}, {3, 1}}},
+		{"Difference", a.Difference(b), []item{{1, 1}, {2, 1}}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []item{{0, 1}, {1, 1}, {1, 3}, {2, 1}}},
+	} {
+		if got := tt.got.Elems(); !slices.Equal(got, tt.want) {
+			t.Errorf("a.%s(b); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+
+	// The elements of c are strictly increasing, so they're merged with the runs of a.
+	c := NewSortedBTreeCmpEqFunc(byKey, eq, item{0, 1}, item{1, 2}, item{3, 1})
+	for _, tt := range []struct {
+		name string
+		got  Set[item]
+		want []item
+	}{
+		{"Intersection", a.Intersection(c), []item{{1, 2}, {3, 1}}},
+		{"Difference", a.Difference(c), []item{{1, 1}, {2, 1}}},
+		{"SymmetricDifference", a.SymmetricDifference(c), []item{{0, 1}, {1, 1}, {2, 1}}},
+	} {
+		if got := tt.got.Elems(); !slices.Equal(got, tt.want) {
+			t.Errorf("a.%s(c); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+	if c.Remove(item{0, 1}); !a.ContainsSet(c) {
+		t.Errorf("a.ContainsSet(c); got: false; want: true")
+	}
+	c.Insert(item{1, 3})
+	if c.Remove(item{1, 2}); a.ContainsSet(c) {
+		t.Errorf("a.ContainsSet(c) after replacing {1 2} with {1 3}; got: true; want: false")
+	}
+	a.RemoveSet(NewSortedBTreeCmpEqFunc(byKey, eq, item{1, 1}, item{2, 2}, item{3, 1}))
+	if got, want := a.Elems(), []item{{1, 2}, {2, 1}}; !slices.Equal(got, want) {
+		t.Errorf("a.RemoveSet(...); got: %v; want: %v", got, want)
+	}
+}
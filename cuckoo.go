@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"math/bits"
+	"math/rand/v2"
+)
+
+const (
+	cuckooBucketSize = 4
+	cuckooMaxKicks   = 500
+	cuckooMaxGrowths = 4    // Times NewCuckooSet doubles a filter's size before giving up.
+	cuckooLoad       = 0.95 // Target load factor when sizing a filter.
+)
+
+// A Cuckoo is a cuckoo filter, which is a space-efficient approximate set
+// that supports removal. It stores a fingerprint of each element in one of
+// two candidate buckets, so it may report that it contains an element that
+// was never inserted, but it never reports that it doesn't contain an element
+// that was inserted and not removed.
+//
+// Inserting an element more than once stores multiple fingerprints, each of
+// which must be removed. Only elements that were inserted may be removed,
+// otherwise the fingerprint of another element may be removed instead.
+//
+// The zero value is not usable. Use NewCuckoo to create a cuckoo filter.
+type Cuckoo[E any] struct {
+	words  []uint64 // Buckets of fingerprints packed fpBits each, where zero is empty.
+	mask   uint64   // Number of buckets minus one.
+	fpBits int
+	slots  int // Number of fingerprints the filter can hold.
+	n      int
+	hash   func(E) uint64
+}
+
+// NewCuckoo returns a cuckoo filter sized to hold n elements with fingerprints
+// of the given number of bits, which must be between 1 and 16 inclusive.
+// The false positive rate is approximately 8 / 2^fpBits. The fingerprints are
+// packed, so each uses fpBits bits of memory.
+// The hash function must distribute elements uniformly over all 64 bits.
+func NewCuckoo[E any](n, fpBits int, hash func(E) uint64) *Cuckoo[E] {
+	if fpBits < 1 || fpBits > 16 {
+		panic("sets: cuckoo filter fingerprint size out of range")
+	}
+	buckets := max(uint64(float64(n)/cuckooBucketSize/cuckooLoad)+1, 1)
+	buckets = 1 << bits.Len64(buckets-1) // Round up to a power of two.
+	slots := buckets * cuckooBucketSize
+	return &Cuckoo[E]{
+		words:  make([]uint64, (slots*uint64(fpBits)+63)/64),
+		mask:   buckets - 1,
+		fpBits: fpBits,
+		slots:  int(slots),
+		hash:   hash,
+	}
+}
+
+// NewCuckooSet returns a cuckoo filter initialized with the elements of the given set
+// and sized to hold them. See NewCuckoo for a description of the parameters.
+//
+// If the elements don't fit, the filter's size is doubled, up to a limit.
+// It returns false if they still don't fit, which happens when more than
+// 8 elements have the same hash, since they share the same two buckets of 4.
+func NewCuckooSet[E any](set Set[E], fpBits int, hash func(E) uint64) (*Cuckoo[E], bool) {
+	n := set.Len()
+	for range cuckooMaxGrowths + 1 {
+		f := NewCuckoo(n, fpBits, hash)
+		if f.InsertSet(set) {
+			return f, true
+		}
+		n *= 2
+	}
+	return nil, false
+}
+
+// Contains returns a value indicating if the given element may be in the filter.
+func (f *Cuckoo[E]) Contains(elem E) bool {
+	fp, i1, i2 := f.locate(elem)
+	return f.find(i1, fp) >= 0 || f.find(i2, fp) >= 0
+}
+
+// ContainsAll returns a value indicating if all the given elements may be in the filter.
+func (f *Cuckoo[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if !f.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Insert adds the given element to the filter. It returns false, leaving
+// the filter unchanged, if there's no room for the element.
+func (f *Cuckoo[E]) Insert(elem E) bool {
+	fp, i1, i2 := f.locate(elem)
+	if f.place(i1, fp) || f.place(i2, fp) {
+		f.n++
+		return true
+	}
+
+	// Both buckets are full, so evict fingerprints to their alternate buckets
+	// until one fits. Record the path so it can be undone if none fit.
+	var path []int
+	i := i1
+	if rand.IntN(2) == 0 {
+		i = i2
+	}
+	for range cuckooMaxKicks {
+		slot := int(i)*cuckooBucketSize + rand.IntN(cuckooBucketSize)
+		path = append(path, slot)
+		fp = f.swap(slot, fp)
+		i = f.alt(i, fp)
+		if f.place(i, fp) {
+			f.n++
+			return true
+		}
+	}
+	for k := len(path) - 1; k >= 0; k-- {
+		fp = f.swap(path[k], fp)
+	}
+	return false
+}
+
+// InsertAll adds the given elements to the filter. It returns false if there
+// wasn't room for all of them, in which case some of them may have been added.
+func (f *Cuckoo[E]) InsertAll(elems ...E) bool {
+	for _, e := range elems {
+		if !f.Insert(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// InsertSet adds the elements of the given set to the filter. It returns false if
+// there wasn't room for all of them, in which case some of them may have been added.
+func (f *Cuckoo[E]) InsertSet(set Set[E]) bool {
+	ok := true
+	set.Range(func(e E) bool {
+		ok = f.Insert(e)
+		return ok
+	})
+	return ok
+}
+
+// Remove removes the given element from the filter.
+// It returns false if the element wasn't found.
+func (f *Cuckoo[E]) Remove(elem E) bool {
+	fp, i1, i2 := f.locate(elem)
+	for _, i := range [2]uint64{i1, i2} {
+		if k := f.find(i, fp); k >= 0 {
+			f.set(k, 0)
+			f.n--
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of fingerprints in the filter.
+func (f *Cuckoo[E]) Len() int {
+	return f.n
+}
+
+// Cap returns the number of fingerprints the filter can hold.
+func (f *Cuckoo[E]) Cap() int {
+	return f.slots
+}
+
+// LoadFactor returns the fraction of the filter's capacity that is used.
+func (f *Cuckoo[E]) LoadFactor() float64 {
+	return float64(f.n) / float64(f.slots)
+}
+
+// FingerprintBits returns the number of bits in each fingerprint.
+func (f *Cuckoo[E]) FingerprintBits() int {
+	return f.fpBits
+}
+
+// locate returns the element's fingerprint and candidate buckets.
+func (f *Cuckoo[E]) locate(elem E) (fp uint16, i1, i2 uint64) {
+	h := f.hash(elem)
+	fp = uint16(h >> (64 - f.fpBits))
+	if fp == 0 {
+		fp = 1 // Zero marks an empty slot.
+	}
+	i1 = h & f.mask
+	return fp, i1, f.alt(i1, fp)
+}
+
+// alt returns the alternate bucket for the fingerprint in bucket i.
+// It's an involution, so alt(alt(i, fp), fp) == i.
+func (f *Cuckoo[E]) alt(i uint64, fp uint16) uint64 {
+	return (i ^ mix64(uint64(fp))) & f.mask
+}
+
+// find returns the slot of the fingerprint in bucket i, or -1 if it's not found.
+func (f *Cuckoo[E]) find(i uint64, fp uint16) int {
+	for k := int(i) * cuckooBucketSize; k < int(i+1)*cuckooBucketSize; k++ {
+		if f.get(k) == fp {
+			return k
+		}
+	}
+	return -1
+}
+
+// place stores the fingerprint in an empty slot of bucket i, if there is one.
+func (f *Cuckoo[E]) place(i uint64, fp uint16) bool {
+	if k := f.find(i, 0); k >= 0 {
+		f.set(k, fp)
+		return true
+	}
+	return false
+}
+
+// get returns the fingerprint in slot k.
+func (f *Cuckoo[E]) get(k int) uint16 {
+	bit := uint(k) * uint(f.fpBits)
+	w, off := bit/64, bit%64
+	v := f.words[w] >> off
+	if off+uint(f.fpBits) > 64 { // Straddles two words.
+		v |= f.words[w+1] << (64 - off)
+	}
+	return uint16(v & (1<<f.fpBits - 1))
+}
+
+// set stores the fingerprint in slot k.
+func (f *Cuckoo[E]) set(k int, fp uint16) {
+	bit := uint(k) * uint(f.fpBits)
+	w, off := bit/64, bit%64
+	mask := uint64(1)<<f.fpBits - 1
+	f.words[w] = f.words[w]&^(mask<<off) | uint64(fp)<<off
+	if off+uint(f.fpBits) > 64 { // Straddles two words.
+		f.words[w+1] = f.words[w+1]&^(mask>>(64-off)) | uint64(fp)>>(64-off)
+	}
+}
+
+// swap stores the fingerprint in slot k and returns the one it replaced.
+func (f *Cuckoo[E]) swap(k int, fp uint16) uint16 {
+	old := f.get(k)
+	f.set(k, fp)
+	return old
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var _ Membership[int] = (*Cuckoo[int])(nil)
+
+func TestCuckoo(t *testing.T) {
+	const n = 10000
+
+	set := New[int]()
+	for i := range n {
+		set.Insert(i)
+	}
+	f, ok := NewCuckooSet(set, 16, hashInt)
+	if !ok {
+		t.Fatalf("NewCuckooSet(set, 16, hashInt); got: false; want: true")
+	}
+	if got := f.Len(); got != n {
+		t.Errorf("f.Len(); got: %v; want: %v", got, n)
+	}
+	if lf := f.LoadFactor(); lf <= 0 || lf > 1 {
+		t.Errorf("f.LoadFactor(); got: %v; want: (0, 1]", lf)
+	}
+	for i := range n {
+		if !f.Contains(i) {
+			t.Fatalf("f.Contains(%d); got: false; want: true", i)
+		}
+	}
+
+	// Sample enough elements that the expected number of false positives
+	// isn't so small that a few more make the test flaky.
+	const samples = 10 * n
+	fp := 0
+	for i := n; i < n+samples; i++ {
+		if f.Contains(i) {
+			fp++
+		}
+	}
+	if rate, want := float64(fp)/samples, 2*8/float64(1<<16); rate > want {
+		t.Errorf("false positive rate; got: %v; want: <= %v", rate, want)
+	}
+
+	for i := 0; i < n; i += 2 {
+		if !f.Remove(i) {
+			t.Fatalf("f.Remove(%d); got: false; want: true", i)
+		}
+	}
+	if got := f.Len(); got != n/2 {
+		t.Errorf("f.Len(); got: %v; want: %v", got, n/2)
+	}
+	for i := 1; i < n; i += 2 {
+		if !f.Contains(i) {
+			t.Fatalf("f.Contains(%d); got: false; want: true", i)
+		}
+	}
+	for i := 1; i < n; i += 2 {
+		f.Remove(i)
+	}
+	if got := f.Len(); got != 0 {
+		t.Errorf("f.Len(); got: %v; want: 0", got)
+	}
+	for i := range n {
+		if f.Contains(i) {
+			t.Fatalf("empty.Contains(%d); got: true; want: false", i)
+		}
+	}
+	if f.Remove(0) {
+		t.Errorf("empty.Remove(0); got: true; want: false")
+	}
+}
+
+func TestCuckooDuplicates(t *testing.T) {
+	f := NewCuckoo(10, 8, hashInt)
+	f.Insert(1)
+	f.Insert(1)
+	if got := f.Len(); got != 2 {
+		t.Errorf("f.Len(); got: %v; want: 2", got)
+	}
+	f.Remove(1)
+	if !f.Contains(1) {
+		t.Errorf("f.Contains(1); got: false; want: true")
+	}
+	f.Remove(1)
+	if f.Contains(1) {
+		t.Errorf("f.Contains(1); got: true; want: false")
+	}
+}
+
+func TestCuckooFull(t *testing.T) {
+	f := NewCuckoo(8, 12, hashInt)
+	var inserted []int
+	for i := 0; ; i++ {
+		if !f.Insert(i) {
+			break
+		}
+		inserted = append(inserted, i)
+	}
+	if got, want := f.Len(), len(inserted); got != want {
+		t.Errorf("f.Len(); got: %v; want: %v", got, want)
+	}
+	if f.Len() > f.Cap() {
+		t.Errorf("f.Len() > f.Cap(); %v > %v", f.Len(), f.Cap())
+	}
+	// A failed insert leaves the filter unchanged.
+	if !f.ContainsAll(inserted...) {
+		t.Errorf("f.ContainsAll(inserted...); got: false; want: true")
+	}
+}
+
+func TestCuckooSetCollisions(t *testing.T) {
+	// Elements with the same hash share the same two buckets of 4,
+	// so no amount of growth makes room for more than 8 of them.
+	hash := func(int) uint64 { return 0x12345 }
+	if f, ok := NewCuckooSet(New(1, 2, 3, 4, 5, 6, 7, 8), 8, hash); !ok || f.Len() != 8 {
+		t.Errorf("NewCuckooSet(8 elements); got: %v; want: true", ok)
+	}
+	if f, ok := NewCuckooSet(New(1, 2, 3, 4, 5, 6, 7, 8, 9), 8, hash); ok || f != nil {
+		t.Errorf("NewCuckooSet(9 elements); got: %v, %v; want: nil, false", f, ok)
+	}
+}
+
+func TestCuckooPacked(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for fpBits := 1; fpBits <= 16; fpBits++ {
+		f := NewCuckoo(1000, fpBits, hashInt)
+		if got, want := len(f.words), (f.Cap()*fpBits+63)/64; got != want {
+			t.Fatalf("fpBits=%v: len(f.words); got: %v; want: %v", fpBits, got, want)
+		}
+		want := make([]uint16, f.Cap())
+		for range 10 * f.Cap() {
+			k, fp := rng.Intn(f.Cap()), uint16(rng.Intn(1<<fpBits))
+			if got := f.swap(k, fp); got != want[k] {
+				t.Fatalf("fpBits=%v: f.swap(%v, %v); got: %v; want: %v", fpBits, k, fp, got, want[k])
+			}
+			want[k] = fp
+		}
+		for k, fp := range want {
+			if got := f.get(k); got != fp {
+				t.Fatalf("fpBits=%v: f.get(%v); got: %v; want: %v", fpBits, k, got, fp)
+			}
+		}
+	}
+}
+
+func TestCuckooInvalid(t *testing.T) {
+	for _, fpBits := range []int{0, 17} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewCuckoo(1, %v, hash); want panic", fpBits)
+				}
+			}()
+			NewCuckoo(1, fpBits, hashInt)
+		}()
+	}
+	if got := NewCuckoo(1, 4, hashInt).FingerprintBits(); got != 4 {
+		t.Errorf("f.FingerprintBits(); got: %v; want: 4", got)
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// A fenwick is a Fenwick tree (binary indexed tree) of counts.
+// It updates a count, sums a prefix of the counts, and searches
+// for the count at which a prefix sum is reached in O(log n) time.
+//
+// Node i (counting from one) holds the sum of the counts in (i-lsb(i), i],
+// so appending and truncating counts leaves the other nodes intact.
+type fenwick []int
+
+// reset sets the tree to n counts given by count in O(n) time.
+func (t *fenwick) reset(n int, count func(i int) int) {
+	*t = slices.Grow((*t)[:0], n)[:n]
+	for i := range n {
+		(*t)[i] = count(i)
+	}
+	for i := 1; i <= n; i++ {
+		if j := i + i&-i; j <= n {
+			(*t)[j-1] += (*t)[i-1]
+		}
+	}
+}
+
+// push appends a count.
+func (t *fenwick) push(count int) {
+	i := len(*t) + 1
+	for j := i - 1; j > i-i&-i; j -= j & -j {
+		count += (*t)[j-1]
+	}
+	*t = append(*t, count)
+}
+
+// truncate removes the counts at n and beyond, if any.
+func (t *fenwick) truncate(n int) {
+	if n < len(*t) {
+		*t = (*t)[:n]
+	}
+}
+
+// add adds delta to the count at i.
+func (t fenwick) add(i, delta int) {
+	for i++; i <= len(t); i += i & -i {
+		t[i-1] += delta
+	}
+}
+
+// sum returns the sum of the counts before i.
+func (t fenwick) sum(i int) int {
+	n := 0
+	for ; i > 0; i -= i & -i {
+		n += t[i-1]
+	}
+	return n
+}
+
+// search returns the index i of the count in which the prefix sum exceeds n
+// and the remainder of n after the counts before i, or len(t) if the sum
+// of all the counts doesn't exceed n.
+func (t fenwick) search(n int) (i, rem int) {
+	if len(t) == 0 {
+		return 0, n
+	}
+	for step := 1 << (bits.Len(uint(len(t))) - 1); step > 0; step >>= 1 {
+		if j := i + step; j <= len(t) && t[j-1] <= n {
+			i = j
+			n -= t[j-1]
+		}
+	}
+	return i, n
+}
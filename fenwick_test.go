@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestFenwick(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed: %v", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	var tree fenwick
+	var counts []int
+	check := func(step int) {
+		t.Helper()
+		if len(tree) != len(counts) {
+			t.Fatalf("step %d: len(tree); got: %v; want: %v", step, len(tree), len(counts))
+		}
+		sum := 0
+		for i, c := range counts {
+			if got := tree.sum(i); got != sum {
+				t.Fatalf("step %d: tree.sum(%d); got: %v; want: %v", step, i, got, sum)
+			}
+			for n := sum; n < sum+c; n++ {
+				if i2, rem := tree.search(n); i2 != i || rem != n-sum {
+					t.Fatalf("step %d: tree.search(%d); got: %v, %v; want: %v, %v", step, n, i2, rem, i, n-sum)
+				}
+			}
+			sum += c
+		}
+		if got := tree.sum(len(counts)); got != sum {
+			t.Fatalf("step %d: tree.sum(%d); got: %v; want: %v", step, len(counts), got, sum)
+		}
+		if i, rem := tree.search(sum); i != len(counts) || rem != 0 {
+			t.Fatalf("step %d: tree.search(%d); got: %v, %v; want: %v, 0", step, sum, i, rem, len(counts))
+		}
+	}
+	for step := range 500 {
+		switch rnd.Intn(4) {
+		case 0:
+			c := rnd.Intn(5)
+			tree.push(c)
+			counts = append(counts, c)
+		case 1:
+			if len(counts) > 0 {
+				i := rnd.Intn(len(counts))
+				d := rnd.Intn(5) - counts[i]/2
+				tree.add(i, d)
+				counts[i] += d
+			}
+		case 2:
+			n := rnd.Intn(len(counts) + 1)
+			tree.truncate(n)
+			counts = counts[:n]
+		case 3:
+			counts = counts[:0]
+			for range rnd.Intn(50) {
+				counts = append(counts, rnd.Intn(5))
+			}
+			tree.reset(len(counts), func(i int) int { return counts[i] })
+		}
+		check(step)
+	}
+}
@@ -0,0 +1,200 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Flag is a command-line flag that parses separated lists of elements into a set.
+// It implements flag.Value, flag.Getter, encoding.TextMarshaler, and encoding.TextUnmarshaler.
+//
+// Each time the flag is set, its elements are added to the set, so it accumulates
+// the elements of repeated flags. Unmarshaling text replaces the elements of the set.
+//
+// For example:
+//
+//	allow := sets.Flag[string]{Values: sets.New[string]()}
+//	flag.Var(&allow, "allow", "comma-separated list of allowed names")
+type Flag[E any] struct {
+	// Values is the set into which elements are parsed. It must not be nil
+	// when the flag is set.
+	Values Set[E]
+	// Parse parses an element. If it's nil, elements that implement
+	// encoding.TextUnmarshaler are unmarshaled, and elements whose
+	// underlying type is a string, bool, integer, or float are parsed
+	// with the strconv package.
+	Parse func(string) (E, error)
+	// Format formats an element. If it's nil, elements that implement
+	// encoding.TextMarshaler are marshaled and other elements are
+	// formatted with fmt.Sprint.
+	Format func(E) string
+	// Separator separates elements. If it's empty, a comma is used.
+	Separator string
+}
+
+// String returns the formatted elements of the set joined by the separator.
+// Sorted and Ordered sets are formatted in their order. Other sets are formatted
+// in the natural order of their elements if the underlying type of the elements
+// is an integer, float, or string, and otherwise in the order of the formatted elements.
+func (f *Flag[E]) String() string {
+	if f == nil || f.Values == nil {
+		return ""
+	}
+	return strings.Join(formatElems(f.Values, f.Format), f.separator())
+}
+
+// Set adds the separated elements of the given value to the set.
+// It returns an error without modifying the set if any of them are invalid.
+func (f *Flag[E]) Set(value string) error {
+	elems, err := f.parse(value)
+	if err != nil {
+		return err
+	}
+	if err := validateAll(f.Values, elems); err != nil {
+		return err
+	}
+	f.Values.InsertAll(elems...)
+	return nil
+}
+
+// Get returns the set.
+func (f *Flag[E]) Get() any {
+	return f.Values
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (f *Flag[E]) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It replaces the elements of the set with the separated elements of the text.
+func (f *Flag[E]) UnmarshalText(text []byte) error {
+	elems, err := f.parse(string(text))
+	if err != nil {
+		return err
+	}
+	return replace(f.Values, elems, false)
+}
+
+func (f *Flag[E]) parse(value string) ([]E, error) {
+	if f.Values == nil {
+		return nil, errors.New("sets: Flag.Values is nil")
+	}
+	return parseElems(value, f.separator(), f.Parse)
+}
+
+func (f *Flag[E]) separator() string {
+	if f.Separator == "" {
+		return ","
+	}
+	return f.Separator
+}
+
+// parseElems parses the elements of the value separated by sep, ignoring surrounding
+// spaces and empty elements. If parse is nil, parseElem is used.
+func parseElems[E any](value, sep string, parse func(string) (E, error)) ([]E, error) {
+	if parse == nil {
+		parse = parseElem[E]
+	}
+	var elems []E
+	for _, s := range strings.Split(value, sep) {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		e, err := parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("sets: invalid element %q: %w", s, err)
+		}
+		elems = append(elems, e)
+	}
+	return elems, nil
+}
+
+// parseElem parses an element that implements encoding.TextUnmarshaler
+// or whose underlying type is a string, bool, integer, or float.
+func parseElem[E any](s string) (E, error) {
+	var e E
+	if u, ok := any(&e).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(s))
+		return e, err
+	}
+	v := reflect.ValueOf(&e).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		x, err := strconv.ParseBool(s)
+		if err != nil {
+			return e, err
+		}
+		v.SetBool(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return e, err
+		}
+		v.SetFloat(x)
+	default:
+		return e, fmt.Errorf("unsupported type: %v", v.Type())
+	}
+	return e, nil
+}
+
+// formatElems returns the formatted elements of the set. Sorted and Ordered sets
+// are formatted in their order, other sets are formatted in the natural order of
+// their elements, if there is one, and otherwise in the order of the formatted elements.
+// If format is nil, formatElem is used.
+func formatElems[E any](set Set[E], format func(E) string) []string {
+	if format == nil {
+		format = formatElem[E]
+	}
+	elems := set.Elems()
+	sorted := hasOrder(set)
+	if cmp := naturalCmp[E](); !sorted && cmp != nil {
+		slices.SortFunc(elems, cmp)
+		sorted = true
+	}
+	strs := make([]string, len(elems))
+	for i, e := range elems {
+		strs[i] = format(e)
+	}
+	if !sorted {
+		slices.Sort(strs)
+	}
+	return strs
+}
+
+// formatElem formats an element that implements encoding.TextMarshaler
+// or otherwise with fmt.Sprint.
+func formatElem[E any](e E) string {
+	if m, ok := any(e).(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprint(e)
+}
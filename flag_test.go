@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"flag"
+	"io"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var _ flag.Getter = (*Flag[string])(nil)
+
+func TestFlag(t *testing.T) {
+	allow := Flag[string]{Values: New[string]()}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&allow, "allow", "allowed names")
+	if err := fs.Parse([]string{"--allow=c, a", "--allow", "b,,a"}); err != nil {
+		t.Fatalf("fs.Parse(); unexpected error: %v", err)
+	}
+	if got, want := allow.String(), "a,b,c"; got != want {
+		t.Errorf("allow.String(); got: %q; want: %q", got, want)
+	}
+	if got, want := fs.Lookup("allow").Value.(flag.Getter).Get().(Set[string]).Len(), 3; got != want {
+		t.Errorf("allow.Get().Len(); got: %v; want: %v", got, want)
+	}
+
+	// The zero value is safe for printing defaults.
+	fs.SetOutput(io.Discard)
+	fs.PrintDefaults()
+	if got := (*Flag[string])(nil).String(); got != "" {
+		t.Errorf("nil.String(); got: %q; want: %q", got, "")
+	}
+	if err := new(Flag[string]).Set("a"); err == nil {
+		t.Errorf("zero.Set(); expected error")
+	}
+}
+
+func TestFlagParse(t *testing.T) {
+	ports := Flag[uint16]{Values: New[uint16](), Separator: ";"}
+	if err := ports.Set("443; 80;0x1F90"); err != nil {
+		t.Fatalf("ports.Set(); unexpected error: %v", err)
+	}
+	if got, want := ports.String(), "80;443;8080"; got != want {
+		t.Errorf("ports.String(); got: %q; want: %q", got, want)
+	}
+	if err := ports.Set("70000"); err == nil {
+		t.Errorf("ports.Set(70000); expected error")
+	}
+	if got := ports.Values.Len(); got != 3 {
+		t.Errorf("ports.Values.Len() after error; got: %v; want: 3", got)
+	}
+
+	addrs := Flag[netip.Addr]{Values: New[netip.Addr]()}
+	if err := addrs.Set("10.0.0.2,10.0.0.1,::1"); err != nil {
+		t.Fatalf("addrs.Set(); unexpected error: %v", err)
+	}
+	if got, want := addrs.String(), "10.0.0.1,10.0.0.2,::1"; got != want {
+		t.Errorf("addrs.String(); got: %q; want: %q", got, want)
+	}
+
+	upper := Flag[string]{
+		Values: NewSorted[string](),
+		Parse:  func(s string) (string, error) { return strings.ToUpper(s), nil },
+		Format: strconv.Quote,
+	}
+	if err := upper.Set("b,a"); err != nil {
+		t.Fatalf("upper.Set(); unexpected error: %v", err)
+	}
+	if got, want := upper.String(), `"A","B"`; got != want {
+		t.Errorf("upper.String(); got: %q; want: %q", got, want)
+	}
+
+	type point struct{ X, Y int }
+	points := Flag[point]{Values: New[point]()}
+	if err := points.Set("1"); err == nil {
+		t.Errorf("points.Set(); expected error")
+	}
+}
+
+func TestFlagText(t *testing.T) {
+	f := Flag[int]{Values: New(1, 2)}
+	b, err := f.MarshalText()
+	if err != nil {
+		t.Fatalf("f.MarshalText(); unexpected error: %v", err)
+	}
+	if got, want := string(b), "1,2"; got != want {
+		t.Errorf("f.MarshalText(); got: %q; want: %q", got, want)
+	}
+	if err := f.UnmarshalText([]byte("3,-4")); err != nil {
+		t.Fatalf("f.UnmarshalText(); unexpected error: %v", err)
+	}
+	if got, want := f.String(), "-4,3"; got != want {
+		t.Errorf("f.String(); got: %q; want: %q", got, want)
+	}
+
+	bits := Flag[int]{Values: Synchronized(NewBitSet(1))}
+	if err := bits.Set("2,-1"); err == nil {
+		t.Errorf("bits.Set(negative); expected error")
+	}
+	if err := bits.UnmarshalText([]byte("-1")); err == nil {
+		t.Errorf("bits.UnmarshalText(negative); expected error")
+	}
+	if got, want := bits.String(), "1"; got != want {
+		t.Errorf("bits.String() after errors; got: %q; want: %q", got, want)
+	}
+}
@@ -1,6 +1,6 @@
 module bursavich.dev/sets
 
-go 1.22
+go 1.23
 
 require (
 	github.com/google/go-cmp v0.6.0
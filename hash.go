@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// hashComparable returns the hash of the comparable value with the given seed.
+// Equal values have equal hashes, like the keys of a map. It panics if the value
+// contains an interface holding a type that isn't comparable.
+//
+// It's like maphash.Comparable, which requires Go 1.24.
+//
+// Other than the common types with fast paths, values are hashed by ops that are
+// built with reflection once per type. Only types that contain an interface
+// use reflection for every value, since its dynamic type can vary.
+func hashComparable[E comparable](seed maphash.Seed, v E) uint64 {
+	switch v := any(v).(type) {
+	case string:
+		return maphash.String(seed, v)
+	case int:
+		return hashUint64(seed, uint64(v))
+	case int32:
+		return hashUint64(seed, uint64(v))
+	case int64:
+		return hashUint64(seed, uint64(v))
+	case uint:
+		return hashUint64(seed, uint64(v))
+	case uint32:
+		return hashUint64(seed, uint64(v))
+	case uint64:
+		return hashUint64(seed, v)
+	}
+	ops, ok := hashOpsFor[E]()
+	if !ok {
+		return hashReflect(seed, v)
+	}
+	var h maphash.Hash
+	h.SetSeed(seed)
+	p := unsafe.Pointer(&v)
+	for _, op := range ops {
+		op.write(&h, p)
+	}
+	return h.Sum64()
+}
+
+// hashReflect returns the hash of the comparable value using reflection.
+// It's separate from hashComparable so that only this path moves the value to the heap.
+func hashReflect[E comparable](seed maphash.Seed, v E) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeComparable(&h, reflect.ValueOf(&v).Elem())
+	return h.Sum64()
+}
+
+// A hashOp writes a value of a basic kind at an offset within a comparable value.
+type hashOp struct {
+	off  uintptr
+	kind reflect.Kind
+}
+
+// hashOps are the ops that hash a comparable type, or nil if it must use reflection.
+type hashOps struct {
+	ops []hashOp
+	ok  bool
+}
+
+var hashOpsCache sync.Map // map[reflect.Type]hashOps
+
+// hashOpsFor returns the ops that write the fields of a comparable type and a value
+// indicating whether it can be hashed with them. They're built once per type.
+func hashOpsFor[E comparable]() ([]hashOp, bool) {
+	t := reflect.TypeFor[E]()
+	if v, ok := hashOpsCache.Load(t); ok {
+		h := v.(hashOps)
+		return h.ops, h.ok
+	}
+	ops, ok := appendHashOps(nil, t, 0)
+	hashOpsCache.Store(t, hashOps{ops: ops, ok: ok})
+	return ops, ok
+}
+
+// appendHashOps appends the ops that write a value of type t at offset off.
+// It returns false if the type contains an interface, whose dynamic type must
+// be found with reflection.
+func appendHashOps(ops []hashOp, t reflect.Type, off uintptr) ([]hashOp, bool) {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String, reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		return append(ops, hashOp{off: off, kind: t.Kind()}), true
+	case reflect.Array:
+		ok := true
+		for i := 0; i < t.Len() && ok; i++ {
+			ops, ok = appendHashOps(ops, t.Elem(), off+uintptr(i)*t.Elem().Size())
+		}
+		return ops, ok
+	case reflect.Struct:
+		ok := true
+		for i := 0; i < t.NumField() && ok; i++ {
+			if f := t.Field(i); f.Name != "_" { // Blank fields aren't compared.
+				ops, ok = appendHashOps(ops, f.Type, off+f.Offset)
+			}
+		}
+		return ops, ok
+	}
+	return nil, false
+}
+
+// write writes the value at the op's offset from p to the hash.
+func (op hashOp) write(h *maphash.Hash, p unsafe.Pointer) {
+	p = unsafe.Add(p, op.off)
+	switch op.kind {
+	case reflect.Bool:
+		if *(*bool)(p) {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int:
+		writeUint64(h, uint64(*(*int)(p)))
+	case reflect.Int8:
+		writeUint64(h, uint64(*(*int8)(p)))
+	case reflect.Int16:
+		writeUint64(h, uint64(*(*int16)(p)))
+	case reflect.Int32:
+		writeUint64(h, uint64(*(*int32)(p)))
+	case reflect.Int64:
+		writeUint64(h, uint64(*(*int64)(p)))
+	case reflect.Uint:
+		writeUint64(h, uint64(*(*uint)(p)))
+	case reflect.Uint8:
+		writeUint64(h, uint64(*(*uint8)(p)))
+	case reflect.Uint16:
+		writeUint64(h, uint64(*(*uint16)(p)))
+	case reflect.Uint32:
+		writeUint64(h, uint64(*(*uint32)(p)))
+	case reflect.Uint64:
+		writeUint64(h, *(*uint64)(p))
+	case reflect.Uintptr:
+		writeUint64(h, uint64(*(*uintptr)(p)))
+	case reflect.Float32:
+		writeFloat64(h, float64(*(*float32)(p)))
+	case reflect.Float64:
+		writeFloat64(h, *(*float64)(p))
+	case reflect.Complex64:
+		c := *(*complex64)(p)
+		writeFloat64(h, float64(real(c)))
+		writeFloat64(h, float64(imag(c)))
+	case reflect.Complex128:
+		c := *(*complex128)(p)
+		writeFloat64(h, real(c))
+		writeFloat64(h, imag(c))
+	case reflect.String:
+		h.WriteString(*(*string)(p))
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		writeUint64(h, uint64(uintptr(*(*unsafe.Pointer)(p))))
+	}
+}
+
+func hashUint64(seed maphash.Seed, v uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return maphash.Bytes(seed, b[:])
+}
+
+func writeUint64(h *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	h.Write(b[:])
+}
+
+func writeFloat64(h *maphash.Hash, f float64) {
+	switch {
+	case f == 0:
+		f = 0 // Hash -0 and +0 the same.
+	case f != f:
+		writeUint64(h, rand.Uint64()) // NaN isn't equal to itself.
+		return
+	}
+	writeUint64(h, math.Float64bits(f))
+}
+
+// writeComparable writes the value to the hash such that equal values write the same bytes.
+func writeComparable(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat64(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat64(h, real(c))
+		writeFloat64(h, imag(c))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		writeComparable(h, v.Elem())
+	case reflect.Array:
+		for i := range v.Len() {
+			writeComparable(h, v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			if t.Field(i).Name == "_" {
+				continue // Blank fields aren't compared.
+			}
+			writeComparable(h, v.Field(i))
+		}
+	default:
+		panic("sets: hash of unhashable type " + v.Type().String())
+	}
+}
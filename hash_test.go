@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"hash/maphash"
+	"math"
+	"testing"
+)
+
+type (
+	hashID    int
+	hashPoint struct {
+		x, y float64
+		_    int
+		name string
+	}
+)
+
+func TestHashComparable(t *testing.T) {
+	seed := maphash.MakeSeed()
+	p := new(int)
+	for _, tt := range []struct {
+		name string
+		a, b any
+	}{
+		{"int", 42, 42},
+		{"string", "abc", "abc"},
+		{"zero", math.Copysign(0, -1), 0.0},
+		{"struct", hashPoint{x: 1, y: 2, name: "a"}, hashPoint{x: 1, y: 2, name: "a"}},
+		{"array", [2]string{"a", "b"}, [2]string{"a", "b"}},
+		{"pointer", p, p},
+		{"interface", [1]any{1}, [1]any{1}},
+		{"named", hashID(7), hashID(7)},
+		{"nested", [2]struct {
+			p hashPoint
+			b bool
+		}{{p: hashPoint{x: 1}, b: true}}, [2]struct {
+			p hashPoint
+			b bool
+		}{{p: hashPoint{x: 1}, b: true}}},
+	} {
+		if a, b := hashComparable(seed, tt.a), hashComparable(seed, tt.b); a != b {
+			t.Errorf("%s: hashComparable(%v) = %v; hashComparable(%v) = %v", tt.name, tt.a, a, tt.b, b)
+		}
+	}
+	for _, tt := range []struct {
+		name string
+		a, b any
+	}{
+		{"int", 1, 2},
+		{"string", "ab", "ba"},
+		{"struct", hashPoint{x: 1, y: 2}, hashPoint{x: 2, y: 1}},
+		{"pointer", p, new(int)},
+		{"named", hashID(1), hashID(2)},
+		{"interface", [1]any{1}, [1]any{"1"}},
+	} {
+		if a, b := hashComparable(seed, tt.a), hashComparable(seed, tt.b); a == b {
+			t.Errorf("%s: hashComparable(%v) == hashComparable(%v) = %v", tt.name, tt.a, tt.b, a)
+		}
+	}
+	if nan := math.NaN(); hashComparable(seed, nan) == hashComparable(seed, nan) {
+		t.Errorf("hashComparable(NaN) returned the same hash twice")
+	}
+}
+
+func TestHashComparableAllocs(t *testing.T) {
+	seed := maphash.MakeSeed()
+	p := hashPoint{x: 1, y: 2, name: "a"}
+	if n := testing.AllocsPerRun(100, func() { hashComparable(seed, p) }); n != 0 {
+		t.Errorf("hashComparable(%v) allocs; got: %v; want: 0", p, n)
+	}
+}
+
+func BenchmarkHashComparable(b *testing.B) {
+	seed := maphash.MakeSeed()
+	b.Run("int", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := range b.N {
+			hashComparable(seed, i)
+		}
+	})
+	b.Run("named", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := range b.N {
+			hashComparable(seed, hashID(i))
+		}
+	})
+	b.Run("struct", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := range b.N {
+			hashComparable(seed, hashPoint{x: float64(i), y: 1, name: "a"})
+		}
+	})
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+// A HashFunc returns the hash of an element with the given seed.
+// Equal elements must have equal hashes for any seed.
+// The functions in hash/maphash are useful to implement it.
+type HashFunc[E any] func(seed maphash.Seed, elem E) uint64
+
+// NewHashFunc returns a set initialized with the given elements, which are identified
+// by the hash and equality functions. It allows elements that aren't comparable, like
+// byte slices, or that have a custom notion of equality, like case-insensitive strings,
+// to be stored in a hash table. Each set uses a random seed, so its hashes are unpredictable.
+//
+// Inserting an element replaces the element in the set to which it's equal.
+// Operations with other sets identify their elements with the set's functions.
+func NewHashFunc[E any](hash HashFunc[E], eq EqFunc[E], elems ...E) Set[E] {
+	set := &hashed[E]{
+		seed:    maphash.MakeSeed(),
+		hash:    hash,
+		eq:      eq,
+		buckets: make(map[uint64][]E, len(elems)),
+	}
+	set.InsertAll(elems...)
+	return set
+}
+
+type hashed[E any] struct {
+	seed    maphash.Seed
+	hash    HashFunc[E]
+	eq      EqFunc[E]
+	buckets map[uint64][]E // Elements chained by hash.
+	n       int
+}
+
+// find returns the hash of the element and its index in the bucket or -1 if it isn't in the set.
+func (set *hashed[E]) find(elem E) (uint64, int) {
+	h := set.hash(set.seed, elem)
+	for i, e := range set.buckets[h] {
+		if set.eq(e, elem) {
+			return h, i
+		}
+	}
+	return h, -1
+}
+
+func (set *hashed[E]) Contains(elem E) bool {
+	_, i := set.find(elem)
+	return i >= 0
+}
+
+func (set *hashed[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if _, i := set.find(e); i < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *hashed[E]) ContainsSet(other Set[E]) bool {
+	ok := true
+	other.Range(func(e E) bool {
+		_, i := set.find(e)
+		ok = i >= 0
+		return ok
+	})
+	return ok
+}
+
+func (set *hashed[E]) Insert(elem E) {
+	h, i := set.find(elem)
+	if i >= 0 {
+		set.buckets[h][i] = elem
+		return
+	}
+	set.buckets[h] = append(set.buckets[h], elem)
+	set.n++
+}
+
+func (set *hashed[E]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		set.Insert(e)
+	}
+}
+
+func (set *hashed[E]) InsertSet(other Set[E]) {
+	if set == other {
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Insert(e)
+		return true
+	})
+}
+
+func (set *hashed[E]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set.Insert(e)
+	}
+}
+
+func (set *hashed[E]) Remove(elem E) {
+	h, i := set.find(elem)
+	if i < 0 {
+		return
+	}
+	if b := set.buckets[h]; len(b) == 1 {
+		delete(set.buckets, h)
+	} else {
+		// Copy the bucket instead of modifying it in place, so it's safe to remove elements while ranging.
+		set.buckets[h] = append(b[:i:i], b[i+1:]...)
+	}
+	set.n--
+}
+
+func (set *hashed[E]) RemoveAll(elems ...E) {
+	for _, e := range elems {
+		set.Remove(e)
+	}
+}
+
+func (set *hashed[E]) RemoveSet(other Set[E]) {
+	if set == other {
+		clear(set.buckets)
+		set.n = 0
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Remove(e)
+		return true
+	})
+}
+
+func (set *hashed[E]) Intersection(other Set[E]) Set[E] {
+	s := set.empty()
+	other.Range(func(e E) bool {
+		if h, i := set.find(e); i >= 0 {
+			s.Insert(set.buckets[h][i])
+		}
+		return true
+	})
+	return s
+}
+
+func (set *hashed[E]) Union(other Set[E]) Set[E] {
+	s := set.clone()
+	s.InsertSet(other)
+	return s
+}
+
+func (set *hashed[E]) Difference(other Set[E]) Set[E] {
+	s := set.clone()
+	s.RemoveSet(other)
+	return s
+}
+
+func (set *hashed[E]) SymmetricDifference(other Set[E]) Set[E] {
+	s := set.clone()
+	other.Range(func(e E) bool {
+		if _, i := set.find(e); i >= 0 {
+			s.Remove(e)
+		} else {
+			s.Insert(e)
+		}
+		return true
+	})
+	return s
+}
+
+func (set *hashed[E]) Len() int {
+	return set.n
+}
+
+func (set *hashed[E]) Elems() []E {
+	elems := make([]E, 0, set.n)
+	for _, b := range set.buckets {
+		elems = append(elems, b...)
+	}
+	return elems
+}
+
+func (set *hashed[E]) Range(fn func(elem E) bool) {
+	for _, b := range set.buckets {
+		for _, e := range b {
+			if !fn(e) {
+				return
+			}
+		}
+	}
+}
+
+func (set *hashed[E]) All() iter.Seq[E] {
+	return set.Range
+}
+
+func (set *hashed[E]) Clone() Set[E] {
+	return set.clone()
+}
+
+func (set *hashed[E]) clone() *hashed[E] {
+	s := &hashed[E]{
+		seed:    set.seed,
+		hash:    set.hash,
+		eq:      set.eq,
+		buckets: make(map[uint64][]E, len(set.buckets)),
+		n:       set.n,
+	}
+	for h, b := range set.buckets {
+		s.buckets[h] = append([]E(nil), b...) // Don't share capacity with the original.
+	}
+	return s
+}
+
+// empty returns an empty set with the same seed and functions,
+// so the hashes of the set's elements can be reused.
+func (set *hashed[E]) empty() *hashed[E] {
+	return &hashed[E]{
+		seed:    set.seed,
+		hash:    set.hash,
+		eq:      set.eq,
+		buckets: make(map[uint64][]E),
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"bytes"
+	"hash/maphash"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHashFuncBytes(t *testing.T) {
+	set := NewHashFunc(maphash.Bytes, bytes.Equal, []byte("a"), []byte("b"), []byte("a"))
+	if got := set.Len(); got != 2 {
+		t.Fatalf("set.Len(); got: %v; want: 2", got)
+	}
+	if !set.Contains([]byte("b")) {
+		t.Fatalf("set.Contains(b); got: false; want: true")
+	}
+	if set.Contains([]byte("c")) {
+		t.Fatalf("set.Contains(c); got: true; want: false")
+	}
+	set.Remove([]byte("a"))
+	if got := set.Elems(); len(got) != 1 || string(got[0]) != "b" {
+		t.Fatalf("set.Elems() after set.Remove(a); got: %q; want: [b]", got)
+	}
+}
+
+func TestHashFuncFold(t *testing.T) {
+	hash := func(seed maphash.Seed, s string) uint64 { return maphash.String(seed, strings.ToLower(s)) }
+	set := NewHashFunc(hash, strings.EqualFold, "Go", "Rust")
+	if !set.ContainsAll("GO", "rust") {
+		t.Fatalf("set.ContainsAll(GO, rust); got: false; want: true")
+	}
+	set.Insert("GO")
+	if got := set.Len(); got != 2 {
+		t.Fatalf("set.Len() after set.Insert(GO); got: %v; want: 2", got)
+	}
+	if got, want := slices.Sorted(set.All()), []string{"GO", "Rust"}; !slices.Equal(got, want) {
+		t.Fatalf("set.All() after set.Insert(GO); got: %v; want: %v", got, want)
+	}
+	if got, want := slices.Sorted(set.Union(New("go", "Zig")).All()), []string{"Rust", "Zig", "go"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Union(...); got: %v; want: %v", got, want)
+	}
+	// The other set's elements are distinct to it, but equal to each other in this set.
+	if got := set.SymmetricDifference(New("Zig", "ZIG")).Len(); got != 3 {
+		t.Fatalf("set.SymmetricDifference(Zig, ZIG).Len(); got: %v; want: 3", got)
+	}
+	// The other set's elements are identified with this set's functions.
+	other := New("go", "GO", "Zig")
+	for _, tt := range []struct {
+		name string
+		got  Set[string]
+		want []string
+	}{
+		{"Intersection", set.Intersection(other), []string{"GO"}},
+		{"Difference", set.Difference(other), []string{"Rust"}},
+		{"SymmetricDifference", set.SymmetricDifference(other), []string{"Rust", "Zig"}},
+	} {
+		if got := slices.Sorted(tt.got.All()); !slices.Equal(got, tt.want) {
+			t.Fatalf("set.%s(go, GO, Zig); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHashFuncCollisions(t *testing.T) {
+	// All elements collide, so they're chained in a single bucket.
+	set := NewHashFunc(func(maphash.Seed, int) uint64 { return 0 }, equal[int], 1, 2, 3, 4, 5)
+	clone := set.Clone()
+	for e := range set.All() {
+		if e%2 == 0 {
+			set.Remove(e)
+		}
+	}
+	if got, want := slices.Sorted(set.All()), []int{1, 3, 5}; !slices.Equal(got, want) {
+		t.Fatalf("set.All() after removing while ranging; got: %v; want: %v", got, want)
+	}
+	clone.Insert(6)
+	set.Insert(7)
+	if got, want := slices.Sorted(clone.All()), []int{1, 2, 3, 4, 5, 6}; !slices.Equal(got, want) {
+		t.Fatalf("clone.All(); got: %v; want: %v", got, want)
+	}
+	if got, want := slices.Sorted(set.All()), []int{1, 3, 5, 7}; !slices.Equal(got, want) {
+		t.Fatalf("set.All(); got: %v; want: %v", got, want)
+	}
+}
@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"encoding/binary"
+	"errors"
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// ErrIncompatibleSketches is returned when combining sketches with different parameters.
+var ErrIncompatibleSketches = errors.New("sets: incompatible sketches")
+
+var errInvalidHyperLogLog = errors.New("sets: invalid hyperloglog encoding")
+
+// hllVersion is the version of the binary encoding of a hyperloglog sketch.
+const hllVersion = 1
+
+const (
+	hllSparse = 0
+	hllDense  = 1
+)
+
+// hllValueMask is the mask of the register value in a sparse entry.
+const hllValueMask = 1<<6 - 1
+
+// A HyperLogLog is a sketch that estimates the number of distinct elements
+// added to it using a small, fixed amount of memory.
+//
+// It starts with a sparse representation that only holds the registers that
+// have been set and switches to a dense representation of all the registers
+// once that's more compact.
+//
+// The zero value is not usable. Use NewHyperLogLog to create a sketch.
+type HyperLogLog[E any] struct {
+	p      uint8    // Precision, which is the log2 of the number of registers.
+	sparse []uint32 // Sorted register entries: index<<6 | value.
+	dense  []uint8  // Register values, if not sparse.
+	hash   func(E) uint64
+}
+
+// NewHyperLogLog returns a sketch with 2^precision registers,
+// where precision must be between 4 and 18 inclusive.
+// The standard error of its estimates is approximately 1.04 / sqrt(2^precision).
+// The hash function must distribute elements uniformly over all 64 bits.
+// Sketches may only be combined or decoded if they use the same hash function.
+func NewHyperLogLog[E any](precision int, hash func(E) uint64) *HyperLogLog[E] {
+	if precision < 4 || precision > 18 {
+		panic("sets: hyperloglog precision out of range")
+	}
+	return &HyperLogLog[E]{
+		p:    uint8(precision),
+		hash: hash,
+	}
+}
+
+// Add adds the given element to the sketch.
+func (s *HyperLogLog[E]) Add(elem E) {
+	h := s.hash(elem)
+	idx := uint32(h >> (64 - s.p))
+	w := h<<s.p | 1<<(s.p-1) // Bound the value if the remaining bits are zero.
+	s.set(idx, uint8(bits.LeadingZeros64(w))+1)
+}
+
+// AddSet adds the elements of the given set to the sketch.
+func (s *HyperLogLog[E]) AddSet(set Set[E]) {
+	set.Range(func(e E) bool {
+		s.Add(e)
+		return true
+	})
+}
+
+// AddSeq adds the elements of the given sequence to the sketch.
+func (s *HyperLogLog[E]) AddSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		s.Add(e)
+	}
+}
+
+// Merge adds the elements of other to the sketch, so that it estimates the number
+// of distinct elements added to either of them. It returns ErrIncompatibleSketches
+// if the sketches have different precisions.
+func (s *HyperLogLog[E]) Merge(other *HyperLogLog[E]) error {
+	if s.p != other.p {
+		return ErrIncompatibleSketches
+	}
+	if s.dense == nil && other.dense == nil {
+		s.sparse = mergeRegisters(s.sparse, other.sparse)
+		if len(s.sparse) > s.maxSparse() {
+			s.toDense()
+		}
+		return nil
+	}
+	if s.dense == nil {
+		s.toDense()
+	}
+	other.registers(func(idx uint32, v uint8) {
+		s.dense[idx] = max(s.dense[idx], v)
+	})
+	return nil
+}
+
+// Estimate returns the estimated number of distinct elements added to the sketch.
+func (s *HyperLogLog[E]) Estimate() uint64 {
+	m := float64(uint64(1) << s.p)
+	zeros := m
+	sum := m // Each register contributes 2^-value, which is 1 for zero.
+	s.registers(func(_ uint32, v uint8) {
+		zeros--
+		sum += math.Ldexp(1, -int(v)) - 1
+	})
+	est := hllAlpha(m) * m * m / sum
+	if est <= 2.5*m && zeros > 0 {
+		est = m * math.Log(m/zeros) // Linear counting is more accurate for small estimates.
+	}
+	return uint64(math.Round(est))
+}
+
+// Clone returns a copy of the sketch.
+func (s *HyperLogLog[E]) Clone() *HyperLogLog[E] {
+	return &HyperLogLog[E]{
+		p:      s.p,
+		sparse: slices.Clone(s.sparse),
+		dense:  slices.Clone(s.dense),
+		hash:   s.hash,
+	}
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (s *HyperLogLog[E]) MarshalBinary() ([]byte, error) {
+	b := []byte{hllVersion, s.p}
+	if s.dense != nil {
+		b = append(b, hllDense)
+		return append(b, s.dense...), nil
+	}
+	b = append(b, hllSparse)
+	b = binary.AppendUvarint(b, uint64(len(s.sparse)))
+	prev := uint32(0)
+	for _, e := range s.sparse {
+		b = binary.AppendUvarint(b, uint64(e-prev))
+		prev = e
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// It replaces the contents and precision of the sketch, but retains its hash function,
+// which must be the same as the one used by the encoded sketch.
+func (s *HyperLogLog[E]) UnmarshalBinary(data []byte) error {
+	if len(data) < 3 || data[0] != hllVersion || data[1] < 4 || data[1] > 18 {
+		return errInvalidHyperLogLog
+	}
+	p, kind, data := data[1], data[2], data[3:]
+	maxValue := 64 - p + 1
+	switch kind {
+	case hllDense:
+		if len(data) != 1<<p {
+			return errInvalidHyperLogLog
+		}
+		for _, v := range data {
+			if v > maxValue {
+				return errInvalidHyperLogLog
+			}
+		}
+		s.p, s.sparse, s.dense = p, nil, slices.Clone(data)
+		return nil
+	case hllSparse:
+		n, k := binary.Uvarint(data)
+		if k <= 0 || n > 1<<p {
+			return errInvalidHyperLogLog
+		}
+		data = data[k:]
+		sparse := make([]uint32, 0, n)
+		prev := uint64(0)
+		for range n {
+			delta, k := binary.Uvarint(data)
+			if k <= 0 {
+				return errInvalidHyperLogLog
+			}
+			data = data[k:]
+			e := prev + delta
+			switch {
+			case len(sparse) > 0 && e>>6 <= prev>>6: // Register indices must be increasing.
+				return errInvalidHyperLogLog
+			case e>>6 >= 1<<p || e&hllValueMask == 0 || e&hllValueMask > uint64(maxValue):
+				return errInvalidHyperLogLog
+			}
+			sparse = append(sparse, uint32(e))
+			prev = e
+		}
+		if len(data) != 0 {
+			return errInvalidHyperLogLog
+		}
+		s.p, s.sparse, s.dense = p, sparse, nil
+		return nil
+	}
+	return errInvalidHyperLogLog
+}
+
+// set updates the register at idx to v if it's greater than its current value.
+func (s *HyperLogLog[E]) set(idx uint32, v uint8) {
+	if s.dense != nil {
+		s.dense[idx] = max(s.dense[idx], v)
+		return
+	}
+	i, found := slices.BinarySearchFunc(s.sparse, idx, func(e, idx uint32) int {
+		return int(e>>6) - int(idx)
+	})
+	switch {
+	case found:
+		s.sparse[i] = idx<<6 | max(s.sparse[i]&hllValueMask, uint32(v))
+	case len(s.sparse) < s.maxSparse():
+		s.sparse = slices.Insert(s.sparse, i, idx<<6|uint32(v))
+	default:
+		s.toDense()
+		s.dense[idx] = v
+	}
+}
+
+// registers calls fn with the index and value of each non-zero register.
+func (s *HyperLogLog[E]) registers(fn func(idx uint32, v uint8)) {
+	if s.dense != nil {
+		for i, v := range s.dense {
+			if v != 0 {
+				fn(uint32(i), v)
+			}
+		}
+		return
+	}
+	for _, e := range s.sparse {
+		fn(e>>6, uint8(e&hllValueMask))
+	}
+}
+
+// maxSparse returns the number of sparse entries that use as much memory as the dense registers.
+func (s *HyperLogLog[E]) maxSparse() int {
+	return 1 << s.p / 4
+}
+
+func (s *HyperLogLog[E]) toDense() {
+	s.dense = make([]uint8, 1<<s.p)
+	for _, e := range s.sparse {
+		s.dense[e>>6] = uint8(e & hllValueMask)
+	}
+	s.sparse = nil
+}
+
+// EstimateUnion returns the estimated number of distinct elements added to either sketch.
+// It returns ErrIncompatibleSketches if the sketches have different precisions.
+func EstimateUnion[E any](a, b *HyperLogLog[E]) (uint64, error) {
+	u := a.Clone()
+	if err := u.Merge(b); err != nil {
+		return 0, err
+	}
+	return u.Estimate(), nil
+}
+
+// EstimateIntersection returns the estimated number of distinct elements added to both
+// sketches, using the inclusion-exclusion principle. Its error is relative to the size
+// of the union, so it's inaccurate for intersections that are small relative to the union.
+// It returns ErrIncompatibleSketches if the sketches have different precisions.
+func EstimateIntersection[E any](a, b *HyperLogLog[E]) (uint64, error) {
+	union, err := EstimateUnion(a, b)
+	if err != nil {
+		return 0, err
+	}
+	ea, eb := a.Estimate(), b.Estimate()
+	if ea+eb <= union {
+		return 0, nil
+	}
+	return min(ea+eb-union, ea, eb), nil
+}
+
+// mergeRegisters returns the union of the sorted sparse register entries,
+// using the greater value of registers in both.
+func mergeRegisters(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch x, y := a[0], b[0]; {
+		case x>>6 < y>>6:
+			out = append(out, x)
+			a = a[1:]
+		case x>>6 > y>>6:
+			out = append(out, y)
+			b = b[1:]
+		default:
+			out = append(out, max(x, y))
+			a, b = a[1:], b[1:]
+		}
+	}
+	out = append(out, a...)
+	return append(out, b...)
+}
+
+// hllAlpha returns the bias correction constant for m registers.
+func hllAlpha(m float64) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/m)
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func checkEstimate(t *testing.T, name string, got, want uint64, tolerance float64) {
+	t.Helper()
+	if diff := math.Abs(float64(got) - float64(want)); diff > tolerance*float64(want)+1 {
+		t.Errorf("%s; got: %v; want: %v ± %.0f%%", name, got, want, 100*tolerance)
+	}
+}
+
+func TestHyperLogLog(t *testing.T) {
+	s := NewHyperLogLog(14, hashInt)
+	if got := s.Estimate(); got != 0 {
+		t.Errorf("empty.Estimate(); got: %v; want: 0", got)
+	}
+	for _, n := range []int{10, 100, 1000, 10000, 100000, 1000000} {
+		for i := range n {
+			s.Add(i)
+		}
+		// Adding elements again doesn't change the estimate.
+		s.AddSeq(slices.Values([]int{0, 1, 2}))
+		checkEstimate(t, "s.Estimate()", s.Estimate(), uint64(n), 0.05)
+	}
+	if s.dense == nil {
+		t.Errorf("s is sparse; want dense")
+	}
+}
+
+func TestHyperLogLogSparse(t *testing.T) {
+	s := NewHyperLogLog(14, hashInt)
+	s.AddSet(New(1, 2, 3, 4, 5))
+	if s.dense != nil {
+		t.Errorf("s is dense; want sparse")
+	}
+	if got := s.Estimate(); got != 5 {
+		t.Errorf("s.Estimate(); got: %v; want: 5", got)
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		n    int
+	}{
+		{"sparse", 1000},
+		{"dense", 100000},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewHyperLogLog(14, hashInt)
+			b := NewHyperLogLog(14, hashInt)
+			for i := range tt.n {
+				a.Add(i)
+				b.Add(i + tt.n/2)
+			}
+
+			union, err := EstimateUnion(a, b)
+			if err != nil {
+				t.Fatalf("EstimateUnion(a, b); unexpected error: %v", err)
+			}
+			checkEstimate(t, "EstimateUnion(a, b)", union, uint64(tt.n*3/2), 0.05)
+
+			inter, err := EstimateIntersection(a, b)
+			if err != nil {
+				t.Fatalf("EstimateIntersection(a, b); unexpected error: %v", err)
+			}
+			checkEstimate(t, "EstimateIntersection(a, b)", inter, uint64(tt.n/2), 0.15)
+
+			before := a.Estimate()
+			if err := a.Merge(b); err != nil {
+				t.Fatalf("a.Merge(b); unexpected error: %v", err)
+			}
+			if got := a.Estimate(); got != union {
+				t.Errorf("a.Estimate(); got: %v; want: %v", got, union)
+			}
+			if got := a.Estimate(); got < before {
+				t.Errorf("a.Estimate(); got: %v; want: >= %v", got, before)
+			}
+		})
+	}
+
+	a := NewHyperLogLog(14, hashInt)
+	b := NewHyperLogLog(12, hashInt)
+	if err := a.Merge(b); !errors.Is(err, ErrIncompatibleSketches) {
+		t.Errorf("a.Merge(b); got error: %v; want: %v", err, ErrIncompatibleSketches)
+	}
+	if _, err := EstimateIntersection(a, b); !errors.Is(err, ErrIncompatibleSketches) {
+		t.Errorf("EstimateIntersection(a, b); got error: %v; want: %v", err, ErrIncompatibleSketches)
+	}
+}
+
+func TestHyperLogLogBinary(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		n    int
+	}{
+		{"empty", 0},
+		{"sparse", 1000},
+		{"dense", 100000},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHyperLogLog(12, hashInt)
+			for i := range tt.n {
+				s.Add(i)
+			}
+			data, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatalf("s.MarshalBinary(); unexpected error: %v", err)
+			}
+			r := NewHyperLogLog(4, hashInt)
+			if err := r.UnmarshalBinary(data); err != nil {
+				t.Fatalf("r.UnmarshalBinary(); unexpected error: %v", err)
+			}
+			if got, want := r.Estimate(), s.Estimate(); got != want {
+				t.Errorf("r.Estimate(); got: %v; want: %v", got, want)
+			}
+			if err := r.Merge(s); err != nil {
+				t.Errorf("r.Merge(s); unexpected error: %v", err)
+			}
+			if len(data) > 3 {
+				if err := r.UnmarshalBinary(data[:len(data)-1]); err == nil {
+					t.Errorf("r.UnmarshalBinary(truncated); expected error")
+				}
+			}
+		})
+	}
+
+	r := NewHyperLogLog(4, hashInt)
+	for _, bad := range [][]byte{
+		nil,
+		{hllVersion, 3, hllDense},
+		{hllVersion, 4, 2},
+		{hllVersion, 4, hllDense, 0},
+		{hllVersion, 4, hllSparse, 1, 0},
+		{hllVersion, 4, hllSparse, 2, 1<<6 | 1, 1},
+	} {
+		if err := r.UnmarshalBinary(bad); err == nil {
+			t.Errorf("r.UnmarshalBinary(%v); expected error", bad)
+		}
+	}
+}
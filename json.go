@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+// JSON is a wrapper that encodes a set as a JSON array and decodes a JSON array into a set.
+//
+// Sorted and Ordered sets are encoded in their order. Other sets are encoded in the natural order
+// of their elements if the underlying type of the elements is an integer, float, or string,
+// and otherwise in the order of the elements' encodings, so the output is deterministic.
+//
+// The sets returned by this package also implement json.Marshaler with the same encoding.
+type JSON[E any] struct {
+	// Set is the set to encode or into which to decode. Decoding replaces
+	// its elements, but preserves its type and comparison function.
+	// It must not be nil when decoding.
+	Set Set[E]
+	// RejectDuplicates indicates if decoding should return an error when
+	// the array contains duplicate elements, instead of discarding them.
+	RejectDuplicates bool
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (j JSON[E]) MarshalJSON() ([]byte, error) {
+	if j.Set == nil {
+		return []byte("null"), nil
+	}
+	return marshalJSON(j.Set)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (j *JSON[E]) UnmarshalJSON(data []byte) error {
+	if j.Set == nil {
+		return errors.New("sets: JSON.Set is nil")
+	}
+	var elems []E
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	return replace(j.Set, elems, j.RejectDuplicates)
+}
+
+// replace replaces the elements of the set with the given elements,
+// returning an error without modifying the set if any of the elements
+// are invalid or if reject is true and there are duplicate elements.
+// Concurrent sets are updated atomically.
+func replace[E any](set Set[E], elems []E, reject bool) error {
+	if c, ok := set.(Concurrent[E]); ok {
+		var err error
+		c.Update(func(set Set[E]) {
+			err = replace(set, elems, reject)
+		})
+		return err
+	}
+	if err := validateAll(set, elems); err != nil {
+		return err
+	}
+	if reject {
+		tmp := set.Clone()
+		tmp.RemoveAll(tmp.Elems()...)
+		for _, e := range elems {
+			if tmp.Contains(e) {
+				return fmt.Errorf("sets: duplicate element: %v", e)
+			}
+			tmp.Insert(e)
+		}
+	}
+	set.RemoveAll(set.Elems()...)
+	set.InsertAll(elems...)
+	return nil
+}
+
+type validator[E any] interface {
+	// validate returns an error if the element can't be inserted into the set.
+	validate(elem E) error
+}
+
+// validateAll returns an error if any of the elements can't be inserted into the set.
+func validateAll[E any](set Set[E], elems []E) error {
+	v, ok := set.(validator[E])
+	if !ok {
+		return nil
+	}
+	for _, e := range elems {
+		if err := v.validate(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (set *bitset[E]) validate(elem E) error {
+	if elem < 0 {
+		return fmt.Errorf("sets: negative element in bit set: %v", elem)
+	}
+	return nil
+}
+
+func (set *synchronized[E]) validate(elem E) error {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if v, ok := set.set.(validator[E]); ok {
+		return v.validate(elem)
+	}
+	return nil
+}
+
+// marshalJSON returns the JSON array encoding of the set.
+func marshalJSON[E any](set Set[E]) ([]byte, error) {
+	elems := set.Elems()
+	if hasOrder(set) {
+		return json.Marshal(nonNil(elems))
+	}
+	if cmp := naturalCmp[E](); cmp != nil {
+		slices.SortFunc(elems, cmp)
+		return json.Marshal(nonNil(elems))
+	}
+	raw := make([]json.RawMessage, len(elems))
+	for i, e := range elems {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		raw[i] = b
+	}
+	slices.SortFunc(raw, func(a, b json.RawMessage) int { return bytes.Compare(a, b) })
+	return json.Marshal(raw)
+}
+
+// hasOrder reports if the set is Sorted or Ordered, so its elements have a meaningful order.
+func hasOrder[E any](set Set[E]) bool {
+	switch set.(type) {
+	case Sorted[E], Ordered[E]:
+		return true
+	}
+	return false
+}
+
+// nonNil returns an empty list if the list is nil, so it's encoded as an empty array.
+func nonNil[E any](elems []E) []E {
+	if elems == nil {
+		return []E{}
+	}
+	return elems
+}
+
+// naturalCmp returns a comparison function for the natural order of E
+// if its underlying type is an integer, float, or string, and otherwise nil.
+func naturalCmp[E any]() CmpFunc[E] {
+	switch reflect.TypeFor[E]().Kind() {
+	case reflect.Int:
+		return compareAs[E, int]
+	case reflect.Int8:
+		return compareAs[E, int8]
+	case reflect.Int16:
+		return compareAs[E, int16]
+	case reflect.Int32:
+		return compareAs[E, int32]
+	case reflect.Int64:
+		return compareAs[E, int64]
+	case reflect.Uint:
+		return compareAs[E, uint]
+	case reflect.Uint8:
+		return compareAs[E, uint8]
+	case reflect.Uint16:
+		return compareAs[E, uint16]
+	case reflect.Uint32:
+		return compareAs[E, uint32]
+	case reflect.Uint64:
+		return compareAs[E, uint64]
+	case reflect.Uintptr:
+		return compareAs[E, uintptr]
+	case reflect.Float32:
+		return compareAs[E, float32]
+	case reflect.Float64:
+		return compareAs[E, float64]
+	case reflect.String:
+		return compareAs[E, string]
+	}
+	return nil
+}
+
+// compareAs compares the values as values of T, which must be E's underlying type.
+// It avoids the cost of reflection for every comparison.
+func compareAs[E any, T cmp.Ordered](a, b E) int {
+	return cmp.Compare(*(*T)(unsafe.Pointer(&a)), *(*T)(unsafe.Pointer(&b)))
+}
+
+func (set table[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *ordered[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *sorted[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *btree[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *sharded[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *bitset[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *roaring[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *linked[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *keyed[E, K]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *hashed[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON[E](set)
+}
+
+func (set *persistentSet[E]) MarshalJSON() ([]byte, error) {
+	return set.p.MarshalJSON()
+}
+
+func (set *synchronized[E]) MarshalJSON() ([]byte, error) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return marshalJSON(set.set)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The set is encoded as a JSON array. See JSON for the order of the elements.
+func (p Persistent[E]) MarshalJSON() ([]byte, error) {
+	return marshalJSON(p.AsSet())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Duplicate elements in the JSON array are discarded.
+func (p *Persistent[E]) UnmarshalJSON(data []byte) error {
+	var elems []E
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	*p = NewPersistent(elems...)
+	return nil
+}
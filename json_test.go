@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	type point struct{ X, Y int }
+	for _, tt := range []struct {
+		name string
+		set  any
+		want string
+	}{
+		{"table", New(3, 1, 2), "[1,2,3]"},
+		{"table/empty", New[int](), "[]"},
+		{"table/string", New("b", "c", "a"), `["a","b","c"]`},
+		{"table/struct", New(point{2, 1}, point{1, 2}), `[{"X":1,"Y":2},{"X":2,"Y":1}]`},
+		{"ordered", NewSorted(3, 1, 2), "[1,2,3]"},
+		{"sorted", NewSortedCmpFunc(func(a, b int) int { return cmp.Compare(b, a) }, 3, 1, 2), "[3,2,1]"},
+		{"btree", NewSortedBTree(3, 1, 2), "[1,2,3]"},
+		{"concurrent", NewConcurrent(3, 1, 2), "[1,2,3]"},
+		{"concurrent/sorted", Synchronized(NewSortedCmpFunc(func(a, b int) int { return cmp.Compare(b, a) }, 3, 1, 2)), "[3,2,1]"},
+		{"sharded", NewSharded(4, 3, 1, 2), "[1,2,3]"},
+		{"persistent", NewPersistent(3, 1, 2), "[1,2,3]"},
+		{"persistent/set", NewPersistent(3, 1, 2).AsSet(), "[1,2,3]"},
+		{"bitset", NewBitSet(3, 1, 2), "[1,2,3]"},
+		{"roaring", NewRoaring[uint32](3, 1, 2), "[1,2,3]"},
+		{"wrapper", JSON[int]{Set: New(3, 1, 2)}, "[1,2,3]"},
+		{"wrapper/nil", JSON[int]{}, "null"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.set)
+			if err != nil {
+				t.Fatalf("json.Marshal(); unexpected error: %v", err)
+			}
+			if got := string(b); got != tt.want {
+				t.Errorf("json.Marshal(); got: %s; want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+	for _, tt := range []struct {
+		name string
+		set  Set[int]
+		want []int
+	}{
+		{"table", New(9), []int{1, 2, 3}},
+		{"sorted", NewSortedCmpFunc(desc, 9), []int{3, 2, 1}},
+		{"btree", NewSortedBTreeCmpFunc(desc, 9), []int{3, 2, 1}},
+		{"concurrent", NewConcurrent(9), []int{1, 2, 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := tt.set
+			if err := json.Unmarshal([]byte("[2,3,1,2]"), &JSON[int]{Set: set}); err != nil {
+				t.Fatalf("json.Unmarshal(); unexpected error: %v", err)
+			}
+			got := set.Elems()
+			if _, ok := set.(Sorted[int]); !ok {
+				slices.Sort(got)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("set.Elems(); got: %v; want: %v", got, tt.want)
+			}
+
+			err := json.Unmarshal([]byte("[4,5,4]"), &JSON[int]{Set: set, RejectDuplicates: true})
+			if err == nil {
+				t.Fatalf("json.Unmarshal(duplicates); expected error")
+			}
+			if got := set.Len(); got != 3 {
+				t.Errorf("set.Len() after error; got: %v; want: 3", got)
+			}
+		})
+	}
+
+	bits := NewBitSet(1)
+	if err := json.Unmarshal([]byte("[2,-1]"), &JSON[int]{Set: bits}); err == nil {
+		t.Errorf("json.Unmarshal(negative into bit set); expected error")
+	}
+	if got := bits.Elems(); !slices.Equal(got, []int{1}) {
+		t.Errorf("bits.Elems() after error; got: %v; want: [1]", got)
+	}
+	if err := json.Unmarshal([]byte("[1]"), &JSON[int]{}); err == nil {
+		t.Errorf("json.Unmarshal(nil set); expected error")
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), &JSON[int]{Set: New[int]()}); err == nil {
+		t.Errorf("json.Unmarshal(object); expected error")
+	}
+
+	var p Persistent[int]
+	if err := json.Unmarshal([]byte("[1,2,2]"), &p); err != nil {
+		t.Fatalf("json.Unmarshal(persistent); unexpected error: %v", err)
+	}
+	if got := p.Len(); got != 2 || !p.ContainsAll(1, 2) {
+		t.Errorf("p.Elems(); got: %v; want: [1 2]", p.Elems())
+	}
+}
+
+func TestNaturalCmp(t *testing.T) {
+	type (
+		level int8
+		name  string
+		ratio float32
+	)
+	if got := naturalCmp[level]()(-2, 1); got != -1 {
+		t.Errorf("naturalCmp[level]()(-2, 1); got: %v; want: -1", got)
+	}
+	if got := naturalCmp[name]()("b", "a"); got != 1 {
+		t.Errorf("naturalCmp[name]()(b, a); got: %v; want: 1", got)
+	}
+	if got := naturalCmp[ratio]()(0.5, 0.5); got != 0 {
+		t.Errorf("naturalCmp[ratio]()(0.5, 0.5); got: %v; want: 0", got)
+	}
+	if got := naturalCmp[uint64]()(1<<63, 1); got != 1 {
+		t.Errorf("naturalCmp[uint64]()(1<<63, 1); got: %v; want: 1", got)
+	}
+	if naturalCmp[[2]int]() != nil {
+		t.Errorf("naturalCmp[[2]int](); got: non-nil; want: nil")
+	}
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+
+	"golang.org/x/exp/maps"
+)
+
+// Keyed is a set whose elements are identified by a key derived from each element.
+type Keyed[E any, K comparable] interface {
+	Set[E]
+
+	// Get returns the element in the set with the given key.
+	// It returns false if there is no such element.
+	Get(key K) (E, bool)
+}
+
+// NewKeyFunc returns a set initialized with the given elements, which are identified
+// by the key function. It allows elements that aren't comparable, like structs with
+// slice or map fields, to be stored in a hash table.
+//
+// Elements with the same key are considered equal, so inserting an element replaces
+// the element in the set with the same key. The key function must be deterministic.
+// Operations with other sets identify their elements with the set's key function.
+func NewKeyFunc[E any, K comparable](key func(E) K, elems ...E) Keyed[E, K] {
+	set := &keyed[E, K]{key: key, elems: make(map[K]E, len(elems))}
+	set.InsertAll(elems...)
+	return set
+}
+
+type keyed[E any, K comparable] struct {
+	key   func(E) K
+	elems map[K]E
+}
+
+func (set *keyed[E, K]) Get(key K) (E, bool) {
+	e, ok := set.elems[key]
+	return e, ok
+}
+
+func (set *keyed[E, K]) Contains(elem E) bool {
+	_, ok := set.elems[set.key(elem)]
+	return ok
+}
+
+func (set *keyed[E, K]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if _, ok := set.elems[set.key(e)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *keyed[E, K]) ContainsSet(other Set[E]) bool {
+	ok := true
+	other.Range(func(e E) bool {
+		_, ok = set.elems[set.key(e)]
+		return ok
+	})
+	return ok
+}
+
+func (set *keyed[E, K]) Insert(elem E) {
+	set.elems[set.key(elem)] = elem
+}
+
+func (set *keyed[E, K]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		set.elems[set.key(e)] = e
+	}
+}
+
+func (set *keyed[E, K]) InsertSet(other Set[E]) {
+	other.Range(func(e E) bool {
+		set.elems[set.key(e)] = e
+		return true
+	})
+}
+
+func (set *keyed[E, K]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set.elems[set.key(e)] = e
+	}
+}
+
+func (set *keyed[E, K]) Remove(elem E) {
+	delete(set.elems, set.key(elem))
+}
+
+func (set *keyed[E, K]) RemoveAll(elems ...E) {
+	for _, e := range elems {
+		delete(set.elems, set.key(e))
+	}
+}
+
+func (set *keyed[E, K]) RemoveSet(other Set[E]) {
+	if set == other {
+		clear(set.elems)
+		return
+	}
+	other.Range(func(e E) bool {
+		delete(set.elems, set.key(e))
+		return true
+	})
+}
+
+func (set *keyed[E, K]) Intersection(other Set[E]) Set[E] {
+	s := set.empty()
+	other.Range(func(e E) bool {
+		k := set.key(e)
+		if e, ok := set.elems[k]; ok {
+			s.elems[k] = e
+		}
+		return true
+	})
+	return s
+}
+
+func (set *keyed[E, K]) Union(other Set[E]) Set[E] {
+	s := set.clone()
+	s.InsertSet(other)
+	return s
+}
+
+func (set *keyed[E, K]) Difference(other Set[E]) Set[E] {
+	s := set.clone()
+	s.RemoveSet(other)
+	return s
+}
+
+func (set *keyed[E, K]) SymmetricDifference(other Set[E]) Set[E] {
+	s := set.clone()
+	other.Range(func(e E) bool {
+		if k := set.key(e); set.containsKey(k) {
+			delete(s.elems, k)
+		} else {
+			s.elems[k] = e
+		}
+		return true
+	})
+	return s
+}
+
+func (set *keyed[E, K]) Len() int {
+	return len(set.elems)
+}
+
+func (set *keyed[E, K]) Elems() []E {
+	return maps.Values(set.elems)
+}
+
+func (set *keyed[E, K]) Range(fn func(elem E) bool) {
+	for _, e := range set.elems {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (set *keyed[E, K]) All() iter.Seq[E] {
+	return set.Range
+}
+
+func (set *keyed[E, K]) Clone() Set[E] {
+	return set.clone()
+}
+
+func (set *keyed[E, K]) clone() *keyed[E, K] {
+	s := &keyed[E, K]{key: set.key, elems: make(map[K]E, len(set.elems))}
+	for k, e := range set.elems {
+		s.elems[k] = e
+	}
+	return s
+}
+
+func (set *keyed[E, K]) empty() *keyed[E, K] {
+	return &keyed[E, K]{key: set.key, elems: make(map[K]E)}
+}
+
+func (set *keyed[E, K]) containsKey(key K) bool {
+	_, ok := set.elems[key]
+	return ok
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+type keyedItem struct {
+	id   string
+	tags []string
+}
+
+func keyedItemID(v keyedItem) string { return v.id }
+
+func keyedItemIDs(set Set[keyedItem]) []string {
+	var ids []string
+	for v := range set.All() {
+		ids = append(ids, v.id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestKeyFunc(t *testing.T) {
+	set := NewKeyFunc(keyedItemID,
+		keyedItem{"b", []string{"x"}},
+		keyedItem{"a", nil},
+		keyedItem{"b", []string{"y"}},
+	)
+	if got, want := keyedItemIDs(set), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("set.All(); got: %v; want: %v", got, want)
+	}
+	if v, ok := set.Get("b"); !ok || !slices.Equal(v.tags, []string{"y"}) {
+		t.Fatalf("set.Get(b); got: %v, %v; want: {b [y]}, true", v, ok)
+	}
+	if _, ok := set.Get("c"); ok {
+		t.Fatalf("set.Get(c); got: true; want: false")
+	}
+	if !set.Contains(keyedItem{id: "a", tags: []string{"z"}}) {
+		t.Fatalf("set.Contains({a [z]}); got: false; want: true")
+	}
+
+	set.Insert(keyedItem{"a", []string{"z"}})
+	if v, _ := set.Get("a"); !slices.Equal(v.tags, []string{"z"}) {
+		t.Fatalf("set.Get(a) after set.Insert({a [z]}); got: %v; want: {a [z]}", v)
+	}
+	set.Remove(keyedItem{id: "b"})
+	if got, want := keyedItemIDs(set), []string{"a"}; !slices.Equal(got, want) {
+		t.Fatalf("set.All() after set.Remove({b}); got: %v; want: %v", got, want)
+	}
+
+	clone := set.Clone().(Keyed[keyedItem, string])
+	clone.Insert(keyedItem{id: "c"})
+	if _, ok := set.Get("c"); ok {
+		t.Fatalf("set.Get(c) after clone.Insert({c}); got: true; want: false")
+	}
+}
+
+func TestKeyFuncOps(t *testing.T) {
+	items := func(ids ...string) []keyedItem {
+		v := make([]keyedItem, len(ids))
+		for i, id := range ids {
+			v[i] = keyedItem{id: id}
+		}
+		return v
+	}
+	for _, otherTyp := range []struct {
+		name   string
+		newSet func(elems ...keyedItem) Set[keyedItem]
+	}{
+		{"keyed", func(elems ...keyedItem) Set[keyedItem] { return NewKeyFunc(keyedItemID, elems...) }},
+		{"sorted", func(elems ...keyedItem) Set[keyedItem] {
+			return NewSortedCmpFunc(func(a, b keyedItem) int { return cmp.Compare(a.id, b.id) }, elems...)
+		}},
+	} {
+		t.Run(otherTyp.name, func(t *testing.T) {
+			a := NewKeyFunc(keyedItemID, items("a", "b", "c")...)
+			b := otherTyp.newSet(items("b", "c", "d")...)
+			for _, tt := range []struct {
+				name string
+				got  Set[keyedItem]
+				want []string
+			}{
+				{"Intersection", a.Intersection(b), []string{"b", "c"}},
+				{"Union", a.Union(b), []string{"a", "b", "c", "d"}},
+				{"Difference", a.Difference(b), []string{"a"}},
+				{"SymmetricDifference", a.SymmetricDifference(b), []string{"a", "d"}},
+			} {
+				if _, ok := tt.got.(Keyed[keyedItem, string]); !ok {
+					t.Errorf("a.%s(b); got: %T; want: Keyed", tt.name, tt.got)
+				}
+				if got := keyedItemIDs(tt.got); !slices.Equal(got, tt.want) {
+					t.Errorf("a.%s(b); got: %v; want: %v", tt.name, got, tt.want)
+				}
+			}
+			if !Equal[keyedItem](a.Union(b), b.Union(a)) {
+				t.Errorf("Equal(a.Union(b), b.Union(a)); got: false; want: true")
+			}
+		})
+	}
+}
+
+func TestKeyFuncOpsIdentity(t *testing.T) {
+	// The other set tells items apart by their tags too,
+	// but they're identified by the set's key function.
+	a := NewKeyFunc(keyedItemID, keyedItem{id: "a"}, keyedItem{id: "b"}, keyedItem{id: "c"})
+	b := NewSortedCmpFunc(func(x, y keyedItem) int {
+		return cmp.Or(cmp.Compare(x.id, y.id), slices.Compare(x.tags, y.tags))
+	}, keyedItem{"b", []string{"x"}}, keyedItem{"b", []string{"y"}}, keyedItem{id: "d"})
+	for _, tt := range []struct {
+		name string
+		got  Set[keyedItem]
+		want []string
+	}{
+		{"Intersection", a.Intersection(b), []string{"b"}},
+		{"Difference", a.Difference(b), []string{"a", "c"}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []string{"a", "c", "d"}},
+	} {
+		if got := keyedItemIDs(tt.got); !slices.Equal(got, tt.want) {
+			t.Errorf("a.%s(b); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+	if v, _ := a.Intersection(b).(Keyed[keyedItem, string]).Get("b"); v.tags != nil {
+		t.Errorf("a.Intersection(b).Get(b); got: %v; want: the set's element {b []}", v)
+	}
+}
@@ -0,0 +1,301 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+)
+
+// Ordered is a set that remembers the order in which its elements were inserted.
+// Elems, Range, and All will return the elements in that order. Inserting an
+// element that's already in the set doesn't change its position.
+type Ordered[E any] interface {
+	Set[E]
+
+	// Backward returns an iterator over the elements of the set in reverse order.
+	Backward() iter.Seq[E]
+
+	// First returns the first element in the set.
+	// It returns false if the set is empty.
+	First() (E, bool)
+	// Last returns the last element in the set.
+	// It returns false if the set is empty.
+	Last() (E, bool)
+	// MoveToFront moves the given element to the front of the set.
+	// It returns false if the element isn't in the set.
+	MoveToFront(elem E) bool
+	// MoveToBack moves the given element to the back of the set.
+	// It returns false if the element isn't in the set.
+	MoveToBack(elem E) bool
+}
+
+// NewOrdered returns an insertion-ordered set initialized with the given elements.
+// It's backed by a hash table and a doubly linked list, so Contains, Insert,
+// Remove, MoveToFront, and MoveToBack take constant time.
+//
+// The results of binary operations are also Ordered, with the elements
+// of the set in its order followed by the elements of other in its order.
+//
+// Elements may be removed during iteration. Removed elements that haven't
+// been reached yet aren't returned.
+func NewOrdered[E comparable](elems ...E) Ordered[E] {
+	set := newLinked[E](len(elems))
+	set.InsertAll(elems...)
+	return set
+}
+
+func newLinked[E comparable](n int) *linked[E] {
+	set := &linked[E]{nodes: make(map[E]*lnode[E], n)}
+	set.root.next = &set.root
+	set.root.prev = &set.root
+	return set
+}
+
+type linked[E comparable] struct {
+	nodes map[E]*lnode[E]
+	root  lnode[E] // Sentinel: root.next is the first node and root.prev is the last.
+}
+
+type lnode[E comparable] struct {
+	elem       E
+	prev, next *lnode[E] // Kept after removal, so iterators can continue past the node.
+	removed    bool
+}
+
+func (set *linked[E]) Contains(elem E) bool {
+	_, ok := set.nodes[elem]
+	return ok
+}
+
+func (set *linked[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if _, ok := set.nodes[e]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *linked[E]) ContainsSet(other Set[E]) bool {
+	ok := true
+	other.Range(func(e E) bool {
+		_, ok = set.nodes[e]
+		return ok
+	})
+	return ok
+}
+
+func (set *linked[E]) Insert(elem E) {
+	if _, ok := set.nodes[elem]; ok {
+		return
+	}
+	n := &lnode[E]{elem: elem}
+	set.nodes[elem] = n
+	set.link(n, set.root.prev)
+}
+
+func (set *linked[E]) InsertAll(elems ...E) {
+	for _, e := range elems {
+		set.Insert(e)
+	}
+}
+
+func (set *linked[E]) InsertSet(other Set[E]) {
+	if set == other {
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Insert(e)
+		return true
+	})
+}
+
+func (set *linked[E]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set.Insert(e)
+	}
+}
+
+func (set *linked[E]) Remove(elem E) {
+	if n, ok := set.nodes[elem]; ok {
+		delete(set.nodes, elem)
+		set.unlink(n)
+		n.removed = true
+	}
+}
+
+func (set *linked[E]) RemoveAll(elems ...E) {
+	for _, e := range elems {
+		set.Remove(e)
+	}
+}
+
+func (set *linked[E]) RemoveSet(other Set[E]) {
+	if set == other {
+		for _, n := range set.nodes {
+			n.removed = true
+		}
+		set.nodes = make(map[E]*lnode[E])
+		set.root.next = &set.root
+		set.root.prev = &set.root
+		return
+	}
+	other.Range(func(e E) bool {
+		set.Remove(e)
+		return true
+	})
+}
+
+func (set *linked[E]) Intersection(other Set[E]) Set[E] {
+	s := newLinked[E](0)
+	for e := range set.All() {
+		if other.Contains(e) {
+			s.Insert(e)
+		}
+	}
+	return s
+}
+
+func (set *linked[E]) Union(other Set[E]) Set[E] {
+	s := set.clone()
+	s.InsertSet(other)
+	return s
+}
+
+func (set *linked[E]) Difference(other Set[E]) Set[E] {
+	s := newLinked[E](0)
+	for e := range set.All() {
+		if !other.Contains(e) {
+			s.Insert(e)
+		}
+	}
+	return s
+}
+
+func (set *linked[E]) SymmetricDifference(other Set[E]) Set[E] {
+	s := set.Difference(other).(*linked[E])
+	other.Range(func(e E) bool {
+		if !set.Contains(e) {
+			s.Insert(e)
+		}
+		return true
+	})
+	return s
+}
+
+func (set *linked[E]) Len() int {
+	return len(set.nodes)
+}
+
+func (set *linked[E]) Elems() []E {
+	elems := make([]E, 0, len(set.nodes))
+	for e := range set.All() {
+		elems = append(elems, e)
+	}
+	return elems
+}
+
+func (set *linked[E]) Range(fn func(elem E) bool) {
+	for e := range set.All() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (set *linked[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		// Read the next node before yielding, so the current element may be removed,
+		// and skip past nodes that were removed while yielding.
+		for n := set.root.next; n != &set.root; {
+			next := n.next
+			if !yield(n.elem) {
+				return
+			}
+			for next.removed {
+				next = next.next
+			}
+			n = next
+		}
+	}
+}
+
+func (set *linked[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for n := set.root.prev; n != &set.root; {
+			prev := n.prev
+			if !yield(n.elem) {
+				return
+			}
+			for prev.removed {
+				prev = prev.prev
+			}
+			n = prev
+		}
+	}
+}
+
+func (set *linked[E]) First() (E, bool) {
+	if len(set.nodes) == 0 {
+		var zero E
+		return zero, false
+	}
+	return set.root.next.elem, true
+}
+
+func (set *linked[E]) Last() (E, bool) {
+	if len(set.nodes) == 0 {
+		var zero E
+		return zero, false
+	}
+	return set.root.prev.elem, true
+}
+
+func (set *linked[E]) MoveToFront(elem E) bool {
+	n, ok := set.nodes[elem]
+	if ok {
+		set.unlink(n)
+		set.link(n, &set.root)
+	}
+	return ok
+}
+
+func (set *linked[E]) MoveToBack(elem E) bool {
+	n, ok := set.nodes[elem]
+	if ok {
+		set.unlink(n)
+		set.link(n, set.root.prev)
+	}
+	return ok
+}
+
+func (set *linked[E]) Clone() Set[E] {
+	return set.clone()
+}
+
+func (set *linked[E]) clone() *linked[E] {
+	s := newLinked[E](len(set.nodes))
+	for e := range set.All() {
+		s.Insert(e)
+	}
+	return s
+}
+
+// link inserts n after at.
+func (set *linked[E]) link(n, at *lnode[E]) {
+	n.prev = at
+	n.next = at.next
+	at.next.prev = n
+	at.next = n
+}
+
+// unlink removes n from the list. It keeps n's pointers,
+// so an iterator that's read n can follow them back to the list.
+func (set *linked[E]) unlink(n *lnode[E]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+}
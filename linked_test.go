@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"encoding/json"
+	"iter"
+	"slices"
+	"testing"
+)
+
+func TestOrdered(t *testing.T) {
+	set := NewOrdered("c", "a", "d", "a", "b")
+	if got, want := set.Elems(), []string{"c", "a", "d", "b"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Elems(); got: %v; want: %v", got, want)
+	}
+
+	set.Insert("c")
+	set.Insert("e")
+	set.Remove("d")
+	if got, want := set.Elems(), []string{"c", "a", "b", "e"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Elems() after insert and remove; got: %v; want: %v", got, want)
+	}
+	if got, want := slices.Collect(set.Backward()), []string{"e", "b", "a", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Backward(); got: %v; want: %v", got, want)
+	}
+
+	if !set.MoveToFront("b") {
+		t.Fatalf("set.MoveToFront(b); got: false; want: true")
+	}
+	if !set.MoveToBack("c") {
+		t.Fatalf("set.MoveToBack(c); got: false; want: true")
+	}
+	if set.MoveToFront("x") || set.MoveToBack("x") {
+		t.Fatalf("set.MoveToFront(x) || set.MoveToBack(x); got: true; want: false")
+	}
+	if got, want := set.Elems(), []string{"b", "a", "e", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Elems() after moves; got: %v; want: %v", got, want)
+	}
+	if e, ok := set.First(); !ok || e != "b" {
+		t.Fatalf("set.First(); got: %v, %v; want: b, true", e, ok)
+	}
+	if e, ok := set.Last(); !ok || e != "c" {
+		t.Fatalf("set.Last(); got: %v, %v; want: c, true", e, ok)
+	}
+
+	clone := set.Clone().(Ordered[string])
+	clone.MoveToFront("c")
+	if got, want := set.Elems(), []string{"b", "a", "e", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Elems() after clone.MoveToFront(c); got: %v; want: %v", got, want)
+	}
+
+	set.RemoveSet(set)
+	if got := set.Len(); got != 0 {
+		t.Fatalf("set.Len() after set.RemoveSet(set); got: %v; want: 0", got)
+	}
+	if _, ok := set.First(); ok {
+		t.Fatalf("set.First() of empty set; got: true; want: false")
+	}
+	if _, ok := set.Last(); ok {
+		t.Fatalf("set.Last() of empty set; got: true; want: false")
+	}
+	set.InsertAll("x", "y")
+	if got, want := set.Elems(), []string{"x", "y"}; !slices.Equal(got, want) {
+		t.Fatalf("set.Elems() after reuse; got: %v; want: %v", got, want)
+	}
+}
+
+func TestOrderedRemoveWhileRanging(t *testing.T) {
+	set := NewOrdered(1, 2, 3, 4, 5)
+	var got []int
+	for e := range set.All() {
+		got = append(got, e)
+		set.Remove(e)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Fatalf("set.All() while removing; got: %v; want: %v", got, want)
+	}
+	if got := set.Len(); got != 0 {
+		t.Fatalf("set.Len(); got: %v; want: 0", got)
+	}
+}
+
+func TestOrderedRemoveOthersWhileRanging(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		seq    func(Ordered[int]) iter.Seq[int]
+		remove map[int][]int // Elements to remove when each element is yielded.
+		want   []int
+	}{
+		{"All/later", Ordered[int].All, map[int][]int{1: {2}}, []int{1, 3, 4, 5}},
+		{"All/run", Ordered[int].All, map[int][]int{1: {2}, 3: {4, 5}}, []int{1, 3}},
+		{"All/current", Ordered[int].All, map[int][]int{2: {2, 3, 1}}, []int{1, 2, 4, 5}},
+		{"All/earlier", Ordered[int].All, map[int][]int{3: {1, 2}}, []int{1, 2, 3, 4, 5}},
+		{"Backward/later", Ordered[int].Backward, map[int][]int{5: {4}}, []int{5, 3, 2, 1}},
+		{"Backward/run", Ordered[int].Backward, map[int][]int{5: {4, 3}, 2: {1}}, []int{5, 2}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := NewOrdered(1, 2, 3, 4, 5)
+			var got []int
+			for e := range tt.seq(set) {
+				got = append(got, e)
+				set.RemoveAll(tt.remove[e]...)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("iterating while removing; got: %v; want: %v", got, tt.want)
+			}
+		})
+	}
+
+	set := NewOrdered(1, 2, 3)
+	var got []int
+	for e := range set.All() {
+		got = append(got, e)
+		set.RemoveSet(set)
+	}
+	if want := []int{1}; !slices.Equal(got, want) {
+		t.Fatalf("set.All() while removing all; got: %v; want: %v", got, want)
+	}
+}
+
+func TestOrderedOps(t *testing.T) {
+	a := NewOrdered(5, 1, 4, 2)
+	b := NewOrdered(3, 2, 6, 5)
+	for _, tt := range []struct {
+		name string
+		got  Set[int]
+		want []int
+	}{
+		{"Intersection", a.Intersection(b), []int{5, 2}},
+		{"Union", a.Union(b), []int{5, 1, 4, 2, 3, 6}},
+		{"Difference", a.Difference(b), []int{1, 4}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 4, 3, 6}},
+	} {
+		if _, ok := tt.got.(Ordered[int]); !ok {
+			t.Errorf("a.%s(b); got: %T; want: Ordered", tt.name, tt.got)
+		}
+		if got := tt.got.Elems(); !slices.Equal(got, tt.want) {
+			t.Errorf("a.%s(b); got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOrderedJSON(t *testing.T) {
+	set := NewOrdered("b", "c", "a")
+	b, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("json.Marshal(); unexpected error: %v", err)
+	}
+	if got, want := string(b), `["b","c","a"]`; got != want {
+		t.Fatalf("json.Marshal(); got: %s; want: %s", got, want)
+	}
+	out := NewOrdered[string]()
+	if err := json.Unmarshal(b, &JSON[string]{Set: out}); err != nil {
+		t.Fatalf("json.Unmarshal(); unexpected error: %v", err)
+	}
+	if got, want := out.Elems(), []string{"b", "c", "a"}; !slices.Equal(got, want) {
+		t.Fatalf("out.Elems(); got: %v; want: %v", got, want)
+	}
+	f := Flag[string]{Values: set}
+	if got, want := f.String(), "b,c,a"; got != want {
+		t.Fatalf("f.String(); got: %v; want: %v", got, want)
+	}
+}
@@ -0,0 +1,305 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// UnionAll returns a new set with the elements of all the given sets (A ∪ B ∪ ...).
+// The new set is of the same kind as the first set. It returns nil if there are no sets.
+//
+// If the sets are all backed by sorted slices with the same order, like those returned
+// by NewSorted and NewSortedCmpFunc, they're merged in a single pass instead of pairwise.
+func UnionAll[E any](sets ...Set[E]) Set[E] {
+	if len(sets) == 0 {
+		return nil
+	}
+	if s, ok := mergeAll(sets, false); ok {
+		return s
+	}
+	s := sets[0].Clone()
+	InsertSeq(s, func(yield func(E) bool) {
+		for _, set := range sets[1:] {
+			for e := range set.All() {
+				if !yield(e) {
+					return
+				}
+			}
+		}
+	})
+	return s
+}
+
+// IntersectAll returns a new set with the elements that are in all the given sets (A ∩ B ∩ ...).
+// The new set is of the same kind as the first set. It returns nil if there are no sets.
+//
+// If the sets are all backed by sorted slices with the same order, like those returned
+// by NewSorted and NewSortedCmpFunc, they're merged in a single pass instead of pairwise.
+// Otherwise, the elements of the smallest set are checked against the others.
+func IntersectAll[E any](sets ...Set[E]) Set[E] {
+	if len(sets) == 0 {
+		return nil
+	}
+	if s, ok := mergeAll(sets, true); ok {
+		return s
+	}
+	bySize := slices.Clone(sets)
+	slices.SortFunc(bySize, func(a, b Set[E]) int { return cmp.Compare(a.Len(), b.Len()) })
+	s := emptyOf(sets[0])
+	InsertSeq(s, func(yield func(E) bool) {
+		for e := range bySize[0].All() {
+			if containedByAll(bySize[1:], e) && !yield(e) {
+				return
+			}
+		}
+	})
+	return s
+}
+
+// MergeSeq returns an iterator over the elements of all the given sorted sets
+// in sorted order. Elements that are in more than one of the sets are yielded once.
+// The sets are merged lazily, so the iterator may be stopped early cheaply.
+func MergeSeq[E cmp.Ordered](sets ...Sorted[E]) iter.Seq[E] {
+	return MergeSeqFunc(cmp.Compare[E], sets...)
+}
+
+// MergeSeqFunc returns an iterator over the elements of all the given sorted sets
+// in the order of the comparison function, by which the sets must be sorted.
+// Elements for which cmp(a, b) == 0 are yielded once, from the first set containing one.
+// The sets are merged lazily, so the iterator may be stopped early cheaply.
+func MergeSeqFunc[E any](cmp CmpFunc[E], sets ...Sorted[E]) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		srcs := make([]func() (E, bool), len(sets))
+		for i, set := range sets {
+			if l, ok := set.(sortedLister[E]); ok {
+				elems, _, _ := l.sortedList()
+				srcs[i] = sliceSource(elems)
+				continue
+			}
+			next, stop := iter.Pull(set.All())
+			defer stop()
+			srcs[i] = next
+		}
+		kwayMerge(srcs, cmp, false, func(run []mergeItem[E]) bool {
+			return yield(run[0].elem)
+		})
+	}
+}
+
+func containedByAll[E any](sets []Set[E], elem E) bool {
+	for _, set := range sets {
+		if !set.Contains(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+type sortedLister[E any] interface {
+	// sortedList returns the elements of the set, which are sorted and unique,
+	// and the set's comparison and equality functions.
+	sortedList() ([]E, CmpFunc[E], EqFunc[E])
+	// fromSortedList returns a new set of the same kind with the same parameters
+	// backed by the given elements, which must be sorted and unique.
+	fromSortedList(elems []E) Set[E]
+}
+
+func (set *ordered[E]) sortedList() ([]E, CmpFunc[E], EqFunc[E]) {
+	return set.elems, cmp.Compare[E], equal[E]
+}
+
+func (set *ordered[E]) fromSortedList(elems []E) Set[E] {
+	return &ordered[E]{elems: elems}
+}
+
+func (set *sorted[E]) sortedList() ([]E, CmpFunc[E], EqFunc[E]) {
+	return set.elems, set.cmp, set.eq
+}
+
+func (set *sorted[E]) fromSortedList(elems []E) Set[E] {
+	return &sorted[E]{elems: elems, cmp: set.cmp, eq: set.eq}
+}
+
+// mergeAll returns the union or intersection of the sets with a k-way merge and
+// a value indicating if they could be merged. They can be merged if they're all
+// sortedListers and their elements are in the order of the first set.
+func mergeAll[E any](sets []Set[E], intersect bool) (Set[E], bool) {
+	first, ok := sets[0].(sortedLister[E])
+	if !ok {
+		return nil, false
+	}
+	_, cmp, eq := first.sortedList()
+	srcs := make([]func() (E, bool), len(sets))
+	for i, set := range sets {
+		l, ok := set.(sortedLister[E])
+		if !ok {
+			return nil, false
+		}
+		// Check the order of all the elements up front,
+		// because an intersection may end without reading them all.
+		elems, _, _ := l.sortedList()
+		if !slices.IsSortedFunc(elems, cmp) {
+			return nil, false
+		}
+		srcs[i] = sliceSource(elems)
+	}
+	var elems []E
+	kwayMerge(srcs, cmp, intersect, func(run []mergeItem[E]) bool {
+		start := len(elems)
+		if intersect {
+			elems = appendCommon(elems, run, len(srcs), eq)
+			return true
+		}
+		for _, it := range run {
+			if i := slices.IndexFunc(elems[start:], func(e E) bool { return eq(it.elem, e) }); i >= 0 {
+				elems[start+i] = it.elem // Overwrite existing values, like InsertSet.
+				continue
+			}
+			elems = append(elems, it.elem)
+		}
+		return true
+	})
+	return first.fromSortedList(elems), true
+}
+
+// appendCommon appends the elements of the run that are in all n sources.
+// The element from the first source is kept.
+func appendCommon[E any](elems []E, run []mergeItem[E], n int, eq EqFunc[E]) []E {
+	if len(run) < n {
+		return elems
+	}
+	counted := make([]bool, len(run))
+	for i, a := range run {
+		if counted[i] {
+			continue
+		}
+		// A source's elements may be equal if its equality function differs from
+		// the first set's, so only count each source once. The run is ordered by
+		// source, so its matches are adjacent.
+		count, src := 1, a.src
+		for k := i + 1; k < len(run); k++ {
+			if !counted[k] && eq(a.elem, run[k].elem) {
+				counted[k] = true
+				if run[k].src != src {
+					src = run[k].src
+					count++
+				}
+			}
+		}
+		if count == n {
+			elems = append(elems, a.elem)
+		}
+	}
+	return elems
+}
+
+// sliceSource returns a function that returns the elements of the list in order.
+func sliceSource[E any](list []E) func() (E, bool) {
+	i := 0
+	return func() (E, bool) {
+		if i >= len(list) {
+			var zero E
+			return zero, false
+		}
+		i++
+		return list[i-1], true
+	}
+}
+
+type mergeItem[E any] struct {
+	elem E
+	src  int
+}
+
+// kwayMerge merges the sorted sources with a heap and calls yield with each run of
+// elements for which cmp(a, b) == 0 until yield returns false. Elements in a run are
+// ordered by their source. If all is true, it stops when any of the sources is exhausted,
+// because no later run can contain an element from every source.
+func kwayMerge[E any](srcs []func() (E, bool), cmp CmpFunc[E], all bool, yield func(run []mergeItem[E]) bool) {
+	h := mergeHeap[E]{cmp: cmp, items: make([]mergeItem[E], 0, len(srcs))}
+	for i, next := range srcs {
+		e, ok := next()
+		if !ok {
+			if all {
+				return
+			}
+			continue
+		}
+		h.push(mergeItem[E]{e, i})
+	}
+	var run []mergeItem[E]
+	for len(h.items) > 0 {
+		run = append(run[:0], h.pop())
+		exhausted := false
+		for {
+			it := run[len(run)-1]
+			if e, ok := srcs[it.src](); ok {
+				h.push(mergeItem[E]{e, it.src})
+			} else {
+				exhausted = true
+			}
+			if len(h.items) == 0 || cmp(h.items[0].elem, run[0].elem) != 0 {
+				break
+			}
+			run = append(run, h.pop())
+		}
+		if !yield(run) || (all && exhausted) {
+			return
+		}
+	}
+}
+
+// mergeHeap is a min-heap of items ordered by their elements and then their sources.
+type mergeHeap[E any] struct {
+	items []mergeItem[E]
+	cmp   CmpFunc[E]
+}
+
+func (h *mergeHeap[E]) less(i, k int) bool {
+	if c := h.cmp(h.items[i].elem, h.items[k].elem); c != 0 {
+		return c < 0
+	}
+	return h.items[i].src < h.items[k].src
+}
+
+func (h *mergeHeap[E]) push(it mergeItem[E]) {
+	h.items = append(h.items, it)
+	for i := len(h.items) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !h.less(i, parent) {
+			break
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *mergeHeap[E]) pop() mergeItem[E] {
+	top := h.items[0]
+	n := len(h.items) - 1
+	h.items[0] = h.items[n]
+	h.items[n] = mergeItem[E]{}
+	h.items = h.items[:n]
+	for i := 0; ; {
+		least, left, right := i, 2*i+1, 2*i+2
+		if left < n && h.less(left, least) {
+			least = left
+		}
+		if right < n && h.less(right, least) {
+			least = right
+		}
+		if least == i {
+			break
+		}
+		h.items[i], h.items[least] = h.items[least], h.items[i]
+		i = least
+	}
+	return top
+}
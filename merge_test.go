@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+var mergeTypes = []struct {
+	name   string
+	newSet func(elems ...int) Set[int]
+}{
+	{"table", New[int]},
+	{"ordered", func(elems ...int) Set[int] { return NewSorted(elems...) }},
+	{"sorted", func(elems ...int) Set[int] { return NewSortedCmpFunc(cmp.Compare[int], elems...) }},
+	{"reversed", func(elems ...int) Set[int] { return NewSortedCmpFunc(reverseInt, elems...) }},
+	{"btree", func(elems ...int) Set[int] { return NewSortedBTree(elems...) }},
+	{"linked", func(elems ...int) Set[int] { return NewOrdered(elems...) }},
+}
+
+func TestUnionIntersectAll(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	lists := make([][]int, 5)
+	for i := range lists {
+		for range 50 + r.Intn(50) {
+			lists[i] = append(lists[i], r.Intn(120))
+		}
+	}
+	// Each combination of set types is used for the first set and the rest, so
+	// the inputs are sometimes all sorted slices and sometimes mixed.
+	for _, firstTyp := range mergeTypes {
+		for _, restTyp := range mergeTypes {
+			t.Run(firstTyp.name+"/"+restTyp.name, func(t *testing.T) {
+				sets := []Set[int]{firstTyp.newSet(lists[0]...)}
+				for _, list := range lists[1:] {
+					sets = append(sets, restTyp.newSet(list...))
+				}
+				union, intersection := sets[0].Clone(), sets[0].Clone()
+				for _, set := range sets[1:] {
+					union = union.Union(set)
+					intersection = intersection.Intersection(set)
+				}
+				for _, tt := range []struct {
+					name string
+					got  Set[int]
+					want Set[int]
+				}{
+					{"UnionAll", UnionAll(sets...), union},
+					{"IntersectAll", IntersectAll(sets...), intersection},
+				} {
+					if a, b := reflect.TypeOf(tt.got), reflect.TypeOf(sets[0]); a != b {
+						t.Fatalf("%s(...) type; got: %v; want: %v", tt.name, a, b)
+					}
+					if !Equal(tt.got, tt.want) {
+						t.Fatalf("%s(...); got: %v; want: %v", tt.name, tt.got.Elems(), tt.want.Elems())
+					}
+					if _, ok := tt.got.(Sorted[int]); ok {
+						if got, want := tt.got.Elems(), tt.want.Elems(); !slices.Equal(got, want) {
+							t.Fatalf("%s(...) order; got: %v; want: %v", tt.name, got, want)
+						}
+					}
+				}
+				if got := sets[0].Len(); got != NewSorted(lists[0]...).Len() {
+					t.Fatalf("sets[0] was modified; got: %v elements", got)
+				}
+			})
+		}
+	}
+}
+
+func TestUnionIntersectAllEdges(t *testing.T) {
+	if got := UnionAll[int](); got != nil {
+		t.Errorf("UnionAll(); got: %v; want: nil", got)
+	}
+	if got := IntersectAll[int](); got != nil {
+		t.Errorf("IntersectAll(); got: %v; want: nil", got)
+	}
+	set := NewSorted(1, 2, 3)
+	if got := UnionAll[int](set); !slices.Equal(got.Elems(), set.Elems()) || got == Set[int](set) {
+		t.Errorf("UnionAll(set); got: %v; want: copy of %v", got.Elems(), set.Elems())
+	}
+	if got := IntersectAll[int](set, NewSorted[int]()); got.Len() != 0 {
+		t.Errorf("IntersectAll(set, empty); got: %v; want: []", got.Elems())
+	}
+}
+
+func TestUnionIntersectAllCmpEq(t *testing.T) {
+	type item struct {
+		key, id int
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	eq := func(a, b item) bool { return a == b }
+	a := NewSortedCmpEqFunc(byKey, eq, item{1, 1}, item{1, 2}, item{2, 1})
+	b := NewSortedCmpEqFunc(byKey, eq, item{1, 2}, item{1, 3}, item{2, 1}, item{3, 1})
+	c := NewSortedCmpEqFunc(byKey, eq, item{1, 3}, item{1, 2}, item{2, 1})
+	if got, want := UnionAll[item](a, b, c), a.Union(b).Union(c); !Equal(got, want) {
+		t.Errorf("UnionAll(a, b, c); got: %v; want: %v", got.Elems(), want.Elems())
+	}
+	if got, want := IntersectAll[item](a, b, c).Elems(), []item{{1, 2}, {2, 1}}; !slices.Equal(got, want) {
+		t.Errorf("IntersectAll(a, b, c); got: %v; want: %v", got, want)
+	}
+}
+
+func TestUnionIntersectAllMixedOrder(t *testing.T) {
+	// The second set is only in order by the first set's comparison
+	// function up to an element after the last one they have in common.
+	a := NewSorted(1, 2)
+	b := NewSortedCmpFunc(reverseInt, 1, 2, 5)
+	if got, want := IntersectAll[int](a, b).Elems(), []int{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("IntersectAll(a, b); got: %v; want: %v", got, want)
+	}
+	if got, want := UnionAll[int](a, b).Elems(), []int{1, 2, 5}; !slices.Equal(got, want) {
+		t.Errorf("UnionAll(a, b); got: %v; want: %v", got, want)
+	}
+
+	// Elements that are unique in their own set may be equal in the first set.
+	type item struct {
+		key, id int
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	c := NewSortedCmpFunc(byKey, item{1, 1})
+	d := NewSortedCmpEqFunc(byKey, func(a, b item) bool { return a == b }, item{1, 1}, item{1, 2})
+	if got := IntersectAll[item](c, NewSortedCmpFunc(byKey, item{2, 1}), d); got.Len() != 0 {
+		t.Errorf("IntersectAll(c, other, d); got: %v; want: []", got.Elems())
+	}
+}
+
+func TestMergeSeq(t *testing.T) {
+	a := NewSorted(1, 4, 7, 9)
+	b := NewSortedBTree(2, 4, 8, 9, 10)
+	c := NewBitSet(0, 1, 2, 3)
+	got := slices.Collect(MergeSeq(a, b, c))
+	if want := []int{0, 1, 2, 3, 4, 7, 8, 9, 10}; !slices.Equal(got, want) {
+		t.Fatalf("MergeSeq(a, b, c); got: %v; want: %v", got, want)
+	}
+
+	// Make sure break works.
+	var first []int
+	for e := range MergeSeq(a, b, c) {
+		if first = append(first, e); len(first) == 3 {
+			break
+		}
+	}
+	if want := []int{0, 1, 2}; !slices.Equal(first, want) {
+		t.Fatalf("MergeSeq(a, b, c) stopped after 3; got: %v; want: %v", first, want)
+	}
+
+	rev := slices.Collect(MergeSeqFunc(reverseInt,
+		NewSortedCmpFunc(reverseInt, 1, 5),
+		NewSortedBTreeCmpFunc(reverseInt, 5, 3),
+	))
+	if want := []int{5, 3, 1}; !slices.Equal(rev, want) {
+		t.Fatalf("MergeSeqFunc(reverse, ...); got: %v; want: %v", rev, want)
+	}
+	if got := slices.Collect(MergeSeq[int]()); len(got) != 0 {
+		t.Fatalf("MergeSeq(); got: %v; want: []", got)
+	}
+}
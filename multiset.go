@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// A Multiset, also known as a bag, is a collection of elements
+// which may contain multiple instances of each element.
+type Multiset[E any] interface {
+	// Add adds n instances of the given element to the multiset.
+	// It does nothing if n isn't positive.
+	Add(elem E, n int)
+	// Count returns the number of instances of the given element in the multiset.
+	Count(elem E) int
+	// RemoveN removes up to n instances of the given element from the multiset.
+	// It does nothing if n isn't positive.
+	RemoveN(elem E, n int)
+
+	// Union returns a new multiset with the greater count of each element
+	// in the multiset and other.
+	Union(other Multiset[E]) Multiset[E]
+	// Sum returns a new multiset with the sum of the counts of each element
+	// in the multiset and other.
+	Sum(other Multiset[E]) Multiset[E]
+	// Intersection returns a new multiset with the lesser count of each element
+	// in the multiset and other.
+	Intersection(other Multiset[E]) Multiset[E]
+	// Difference returns a new multiset with the count of each element in other
+	// subtracted from its count in the multiset.
+	Difference(other Multiset[E]) Multiset[E]
+
+	// Len returns the number of instances of all the elements in the multiset.
+	Len() int
+	// Distinct returns a set of the distinct elements in the multiset.
+	Distinct() Set[E]
+	// Range calls the given function with each distinct element of the multiset
+	// and its count until there are no elements remaining or the function returns false.
+	Range(fn func(elem E, n int) bool)
+	// All returns an iterator over the distinct elements of the multiset and their counts.
+	All() iter.Seq2[E, int]
+
+	// Clone returns a copy of the multiset.
+	Clone() Multiset[E]
+}
+
+// NewMultiset returns a multiset initialized with one instance of each of the given elements.
+func NewMultiset[E comparable](elems ...E) Multiset[E] {
+	set := &hashMultiset[E]{counts: make(map[E]int, len(elems))}
+	for _, e := range elems {
+		set.Add(e, 1)
+	}
+	return set
+}
+
+// NewSortedMultiset returns a sorted multiset initialized with one instance
+// of each of the given elements. Its elements are ranged over in sorted order.
+func NewSortedMultiset[E cmp.Ordered](elems ...E) Multiset[E] {
+	return NewSortedMultisetCmpEqFunc(cmp.Compare[E], equal[E], elems...)
+}
+
+// NewSortedMultisetCmpFunc returns a sorted multiset initialized with one instance
+// of each of the given elements. The comparison function is used to order and identify elements.
+func NewSortedMultisetCmpFunc[E any](cmp CmpFunc[E], elems ...E) Multiset[E] {
+	return NewSortedMultisetCmpEqFunc(cmp, func(a, b E) bool { return cmp(a, b) == 0 }, elems...)
+}
+
+// NewSortedMultisetCmpEqFunc returns a sorted multiset initialized with one instance
+// of each of the given elements. The comparison function is only used to order elements
+// and the equality function is used to identify elements, like NewSortedCmpEqFunc.
+func NewSortedMultisetCmpEqFunc[E any](cmp CmpFunc[E], eq EqFunc[E], elems ...E) Multiset[E] {
+	set := &sortedMultiset[E]{set: &sorted[E]{cmp: cmp, eq: eq}}
+	for _, e := range elems {
+		set.Add(e, 1)
+	}
+	return set
+}
+
+type hashMultiset[E comparable] struct {
+	counts map[E]int
+	n      int
+}
+
+func (set *hashMultiset[E]) Add(elem E, n int) {
+	if n > 0 {
+		set.counts[elem] += n
+		set.n += n
+	}
+}
+
+func (set *hashMultiset[E]) Count(elem E) int {
+	return set.counts[elem]
+}
+
+func (set *hashMultiset[E]) RemoveN(elem E, n int) {
+	if n <= 0 {
+		return
+	}
+	c, ok := set.counts[elem]
+	if !ok {
+		return
+	}
+	if n >= c {
+		delete(set.counts, elem)
+		set.n -= c
+		return
+	}
+	set.counts[elem] = c - n
+	set.n -= n
+}
+
+func (set *hashMultiset[E]) Union(other Multiset[E]) Multiset[E] {
+	return unionCounts(set.clone(), other)
+}
+
+func (set *hashMultiset[E]) Sum(other Multiset[E]) Multiset[E] {
+	return sumCounts(set.clone(), other)
+}
+
+func (set *hashMultiset[E]) Intersection(other Multiset[E]) Multiset[E] {
+	return intersectCounts(set, &hashMultiset[E]{counts: make(map[E]int)}, other)
+}
+
+func (set *hashMultiset[E]) Difference(other Multiset[E]) Multiset[E] {
+	return subtractCounts(set.clone(), other)
+}
+
+func (set *hashMultiset[E]) Len() int {
+	return set.n
+}
+
+func (set *hashMultiset[E]) Distinct() Set[E] {
+	s := make(table[E], len(set.counts))
+	for e := range set.counts {
+		s[e] = struct{}{}
+	}
+	return s
+}
+
+func (set *hashMultiset[E]) Range(fn func(elem E, n int) bool) {
+	for e, n := range set.counts {
+		if !fn(e, n) {
+			return
+		}
+	}
+}
+
+func (set *hashMultiset[E]) All() iter.Seq2[E, int] {
+	return set.Range
+}
+
+func (set *hashMultiset[E]) Clone() Multiset[E] {
+	return set.clone()
+}
+
+func (set *hashMultiset[E]) clone() *hashMultiset[E] {
+	counts := make(map[E]int, len(set.counts))
+	for e, n := range set.counts {
+		counts[e] = n
+	}
+	return &hashMultiset[E]{counts: counts, n: set.n}
+}
+
+type sortedMultiset[E any] struct {
+	set    *sorted[E] // Distinct elements.
+	counts []int      // Count of each distinct element.
+	n      int
+}
+
+func (set *sortedMultiset[E]) Add(elem E, n int) {
+	if n <= 0 {
+		return
+	}
+	idx, found := set.set.search(elem)
+	if found {
+		set.counts[idx] += n
+	} else {
+		set.set.elems = slices.Insert(set.set.elems, idx, elem)
+		set.counts = slices.Insert(set.counts, idx, n)
+	}
+	set.n += n
+}
+
+func (set *sortedMultiset[E]) Count(elem E) int {
+	if idx, found := set.set.search(elem); found {
+		return set.counts[idx]
+	}
+	return 0
+}
+
+func (set *sortedMultiset[E]) RemoveN(elem E, n int) {
+	if n <= 0 {
+		return
+	}
+	idx, found := set.set.search(elem)
+	if !found {
+		return
+	}
+	if c := set.counts[idx]; n < c {
+		set.counts[idx] = c - n
+		set.n -= n
+		return
+	}
+	set.n -= set.counts[idx]
+	set.set.elems = slices.Delete(set.set.elems, idx, idx+1)
+	set.counts = slices.Delete(set.counts, idx, idx+1)
+}
+
+func (set *sortedMultiset[E]) Union(other Multiset[E]) Multiset[E] {
+	return unionCounts(set.clone(), other)
+}
+
+func (set *sortedMultiset[E]) Sum(other Multiset[E]) Multiset[E] {
+	return sumCounts(set.clone(), other)
+}
+
+func (set *sortedMultiset[E]) Intersection(other Multiset[E]) Multiset[E] {
+	return intersectCounts(set, &sortedMultiset[E]{set: &sorted[E]{cmp: set.set.cmp, eq: set.set.eq}}, other)
+}
+
+func (set *sortedMultiset[E]) Difference(other Multiset[E]) Multiset[E] {
+	return subtractCounts(set.clone(), other)
+}
+
+func (set *sortedMultiset[E]) Len() int {
+	return set.n
+}
+
+func (set *sortedMultiset[E]) Distinct() Set[E] {
+	return set.set.Clone()
+}
+
+func (set *sortedMultiset[E]) Range(fn func(elem E, n int) bool) {
+	for i, e := range set.set.elems {
+		if !fn(e, set.counts[i]) {
+			return
+		}
+	}
+}
+
+func (set *sortedMultiset[E]) All() iter.Seq2[E, int] {
+	return set.Range
+}
+
+func (set *sortedMultiset[E]) Clone() Multiset[E] {
+	return set.clone()
+}
+
+func (set *sortedMultiset[E]) clone() *sortedMultiset[E] {
+	return &sortedMultiset[E]{
+		set:    set.set.Clone().(*sorted[E]),
+		counts: slices.Clone(set.counts),
+		n:      set.n,
+	}
+}
+
+// unionCounts sets the count of each element in dst to the greater of its count in dst and other.
+func unionCounts[E any](dst, other Multiset[E]) Multiset[E] {
+	other.Range(func(e E, n int) bool {
+		dst.Add(e, n-dst.Count(e))
+		return true
+	})
+	return dst
+}
+
+// sumCounts adds the count of each element in other to its count in dst.
+func sumCounts[E any](dst, other Multiset[E]) Multiset[E] {
+	other.Range(func(e E, n int) bool {
+		dst.Add(e, n)
+		return true
+	})
+	return dst
+}
+
+// intersectCounts adds each element of set to dst, which must be empty,
+// with the lesser of its count in set and other.
+func intersectCounts[E any](set, dst, other Multiset[E]) Multiset[E] {
+	set.Range(func(e E, n int) bool {
+		dst.Add(e, min(n, other.Count(e)))
+		return true
+	})
+	return dst
+}
+
+// subtractCounts subtracts the count of each element in other from its count in dst.
+func subtractCounts[E any](dst, other Multiset[E]) Multiset[E] {
+	other.Range(func(e E, n int) bool {
+		dst.RemoveN(e, n)
+		return true
+	})
+	return dst
+}
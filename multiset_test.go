@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"testing"
+)
+
+var multisetTypes = []struct {
+	name   string
+	newSet func(elems ...string) Multiset[string]
+	sorted bool
+}{
+	{"hash", NewMultiset[string], false},
+	{"sorted", NewSortedMultiset[string], true},
+	{"sortedCmp", func(elems ...string) Multiset[string] { return NewSortedMultisetCmpFunc(cmp.Compare[string], elems...) }, true},
+}
+
+func counts[E comparable](set Multiset[E]) map[E]int {
+	return maps.Collect(set.All())
+}
+
+func TestMultiset(t *testing.T) {
+	for _, typ := range multisetTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet("b", "a", "b", "c", "b")
+			if got, want := counts(set), map[string]int{"a": 1, "b": 3, "c": 1}; !maps.Equal(got, want) {
+				t.Errorf("counts(set); got: %v; want: %v", got, want)
+			}
+			if got := set.Len(); got != 5 {
+				t.Errorf("set.Len(); got: %v; want: 5", got)
+			}
+
+			set.Add("a", 2)
+			set.Add("d", 0)
+			set.Add("d", -1)
+			if got := set.Count("a"); got != 3 {
+				t.Errorf("set.Count(a); got: %v; want: 3", got)
+			}
+			if got := set.Count("d"); got != 0 {
+				t.Errorf("set.Count(d); got: %v; want: 0", got)
+			}
+
+			set.RemoveN("b", 2)
+			set.RemoveN("c", 5)
+			set.RemoveN("d", 1)
+			set.RemoveN("a", -1)
+			if got, want := counts(set), map[string]int{"a": 3, "b": 1}; !maps.Equal(got, want) {
+				t.Errorf("counts(set); got: %v; want: %v", got, want)
+			}
+			if got := set.Len(); got != 4 {
+				t.Errorf("set.Len(); got: %v; want: 4", got)
+			}
+
+			distinct := set.Distinct()
+			if got := distinct.Len(); got != 2 || !distinct.ContainsAll("a", "b") {
+				t.Errorf("set.Distinct(); got: %v; want: [a b]", distinct.Elems())
+			}
+
+			clone := set.Clone()
+			clone.Add("a", 1)
+			if got := set.Count("a"); got != 3 {
+				t.Errorf("set.Count(a) after clone.Add(a, 1); got: %v; want: 3", got)
+			}
+
+			if typ.sorted {
+				var got []string
+				for e := range typ.newSet("c", "a", "b", "a").All() {
+					got = append(got, e)
+				}
+				if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+					t.Errorf("set.All(); got: %v; want: %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMultisetOps(t *testing.T) {
+	for _, typ := range multisetTypes {
+		for _, otherTyp := range multisetTypes {
+			t.Run(typ.name+"/"+otherTyp.name, func(t *testing.T) {
+				a := typ.newSet("a", "a", "a", "b", "c")
+				b := otherTyp.newSet("a", "b", "b", "d")
+				for _, tt := range []struct {
+					name string
+					got  Multiset[string]
+					want map[string]int
+				}{
+					{"Union", a.Union(b), map[string]int{"a": 3, "b": 2, "c": 1, "d": 1}},
+					{"Sum", a.Sum(b), map[string]int{"a": 4, "b": 3, "c": 1, "d": 1}},
+					{"Intersection", a.Intersection(b), map[string]int{"a": 1, "b": 1}},
+					{"Difference", a.Difference(b), map[string]int{"a": 2, "c": 1}},
+				} {
+					if got := counts(tt.got); !maps.Equal(got, tt.want) {
+						t.Errorf("a.%s(b); got: %v; want: %v", tt.name, got, tt.want)
+					}
+					n := 0
+					for _, c := range tt.want {
+						n += c
+					}
+					if got := tt.got.Len(); got != n {
+						t.Errorf("a.%s(b).Len(); got: %v; want: %v", tt.name, got, n)
+					}
+				}
+				if got, want := counts(a), map[string]int{"a": 3, "b": 1, "c": 1}; !maps.Equal(got, want) {
+					t.Errorf("a was modified; got: %v; want: %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestSortedMultisetCmpEq(t *testing.T) {
+	type item struct {
+		key, id int
+	}
+	set := NewSortedMultisetCmpEqFunc(
+		func(a, b item) int { return cmp.Compare(a.key, b.key) },
+		func(a, b item) bool { return a == b },
+		item{1, 1}, item{1, 2}, item{0, 1}, item{1, 1},
+	)
+	var got []item
+	set.Range(func(e item, n int) bool {
+		got = append(got, e)
+		return true
+	})
+	if want := []item{{0, 1}, {1, 1}, {1, 2}}; !slices.Equal(got, want) {
+		t.Errorf("set.Range(); got: %v; want: %v", got, want)
+	}
+	if got := set.Count(item{1, 1}); got != 2 {
+		t.Errorf("set.Count({1 1}); got: %v; want: 2", got)
+	}
+	if got := set.Count(item{1, 3}); got != 0 {
+		t.Errorf("set.Count({1 3}); got: %v; want: 0", got)
+	}
+}
@@ -0,0 +1,608 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+// hamtSeed is shared by all persistent sets so that they may share structure.
+var hamtSeed = maphash.MakeSeed()
+
+// A Persistent is an immutable set of unique elements. It's backed by a hash array
+// mapped trie, so operations that would modify the set instead return a new version
+// of it which shares most of its structure with the original.
+//
+// The zero value is an empty set.
+type Persistent[E comparable] struct {
+	root *hamtNode[E]
+}
+
+// NewPersistent returns a persistent set initialized with the given elements.
+func NewPersistent[E comparable](elems ...E) Persistent[E] {
+	var p Persistent[E]
+	for _, e := range elems {
+		p.root, _ = p.root.with(hamtHash(e), 0, e)
+	}
+	return p
+}
+
+// Contains returns a value indicating if the given element is in the set.
+func (p Persistent[E]) Contains(elem E) bool {
+	return p.root.contains(hamtHash(elem), 0, elem)
+}
+
+// ContainsAll returns a value indicating if all the given elements are in the set.
+func (p Persistent[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if !p.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// With returns a version of the set with the given element.
+func (p Persistent[E]) With(elem E) Persistent[E] {
+	root, _ := p.root.with(hamtHash(elem), 0, elem)
+	return Persistent[E]{root}
+}
+
+// WithAll returns a version of the set with the given elements.
+func (p Persistent[E]) WithAll(elems ...E) Persistent[E] {
+	for _, e := range elems {
+		p = p.With(e)
+	}
+	return p
+}
+
+// Without returns a version of the set without the given element.
+func (p Persistent[E]) Without(elem E) Persistent[E] {
+	root, _ := p.root.without(hamtHash(elem), 0, elem)
+	return Persistent[E]{root}
+}
+
+// WithoutAll returns a version of the set without the given elements.
+func (p Persistent[E]) WithoutAll(elems ...E) Persistent[E] {
+	for _, e := range elems {
+		p = p.Without(e)
+	}
+	return p
+}
+
+// Intersection (A ∩ B) returns a version of the set with only the elements that are also in other.
+func (p Persistent[E]) Intersection(other Persistent[E]) Persistent[E] {
+	return Persistent[E]{intersectHamt(p.root, other.root, 0)}
+}
+
+// Union (A ∪ B) returns a version of the set with the elements of other.
+func (p Persistent[E]) Union(other Persistent[E]) Persistent[E] {
+	return Persistent[E]{unionHamt(p.root, other.root, 0)}
+}
+
+// Difference (A − B) returns a version of the set without the elements of other.
+func (p Persistent[E]) Difference(other Persistent[E]) Persistent[E] {
+	return Persistent[E]{diffHamt(p.root, other.root, 0)}
+}
+
+// SymmetricDifference (A △ B) returns a version of the set with the elements
+// of other that aren't in the set and without the elements that are.
+func (p Persistent[E]) SymmetricDifference(other Persistent[E]) Persistent[E] {
+	return p.Difference(other).Union(other.Difference(p))
+}
+
+// Len returns the size, also known as cardinality, of the set.
+func (p Persistent[E]) Len() int {
+	if p.root == nil {
+		return 0
+	}
+	return p.root.size
+}
+
+// Elems returns a list of the elements in the set.
+func (p Persistent[E]) Elems() []E {
+	elems := make([]E, 0, p.Len())
+	p.Range(func(e E) bool {
+		elems = append(elems, e)
+		return true
+	})
+	return elems
+}
+
+// Range calls the given function with each element of the set until
+// there are no elements remaining or the function returns false.
+func (p Persistent[E]) Range(fn func(elem E) bool) {
+	if p.root != nil {
+		p.root.ascend(fn)
+	}
+}
+
+// All returns an iterator over the elements of the set.
+func (p Persistent[E]) All() iter.Seq[E] {
+	return p.Range
+}
+
+// AsSet returns a Set backed by the persistent set.
+// Changes to the returned set create new versions of the persistent set,
+// so they don't affect the original. Cloning the returned set takes O(1) time.
+func (p Persistent[E]) AsSet() Set[E] {
+	return &persistentSet[E]{p}
+}
+
+type persistentSet[E comparable] struct {
+	p Persistent[E]
+}
+
+func (set *persistentSet[E]) Contains(elem E) bool {
+	return set.p.Contains(elem)
+}
+
+func (set *persistentSet[E]) ContainsAll(elems ...E) bool {
+	return set.p.ContainsAll(elems...)
+}
+
+func (set *persistentSet[E]) ContainsSet(other Set[E]) bool {
+	if other, ok := other.(*persistentSet[E]); ok {
+		return diffHamt(other.p.root, set.p.root, 0) == nil
+	}
+	ok := true
+	other.Range(func(e E) bool {
+		ok = set.p.Contains(e)
+		return ok
+	})
+	return ok
+}
+
+func (set *persistentSet[E]) Insert(elem E) {
+	set.p = set.p.With(elem)
+}
+
+func (set *persistentSet[E]) InsertAll(elems ...E) {
+	set.p = set.p.WithAll(elems...)
+}
+
+func (set *persistentSet[E]) InsertSet(other Set[E]) {
+	if other, ok := other.(*persistentSet[E]); ok {
+		set.p = set.p.Union(other.p)
+		return
+	}
+	other.Range(func(e E) bool {
+		set.p = set.p.With(e)
+		return true
+	})
+}
+
+func (set *persistentSet[E]) Remove(elem E) {
+	set.p = set.p.Without(elem)
+}
+
+func (set *persistentSet[E]) RemoveAll(elems ...E) {
+	set.p = set.p.WithoutAll(elems...)
+}
+
+func (set *persistentSet[E]) RemoveSet(other Set[E]) {
+	if other, ok := other.(*persistentSet[E]); ok {
+		set.p = set.p.Difference(other.p)
+		return
+	}
+	other.Range(func(e E) bool {
+		set.p = set.p.Without(e)
+		return true
+	})
+}
+
+func (set *persistentSet[E]) Intersection(other Set[E]) Set[E] {
+	if other, ok := other.(*persistentSet[E]); ok {
+		return &persistentSet[E]{set.p.Intersection(other.p)}
+	}
+	var p Persistent[E]
+	set.p.Range(func(e E) bool {
+		if other.Contains(e) {
+			p = p.With(e)
+		}
+		return true
+	})
+	return &persistentSet[E]{p}
+}
+
+func (set *persistentSet[E]) Union(other Set[E]) Set[E] {
+	s := &persistentSet[E]{set.p}
+	s.InsertSet(other)
+	return s
+}
+
+func (set *persistentSet[E]) Difference(other Set[E]) Set[E] {
+	if other, ok := other.(*persistentSet[E]); ok {
+		return &persistentSet[E]{set.p.Difference(other.p)}
+	}
+	p := set.p
+	set.p.Range(func(e E) bool {
+		if other.Contains(e) {
+			p = p.Without(e)
+		}
+		return true
+	})
+	return &persistentSet[E]{p}
+}
+
+func (set *persistentSet[E]) SymmetricDifference(other Set[E]) Set[E] {
+	if other, ok := other.(*persistentSet[E]); ok {
+		return &persistentSet[E]{set.p.SymmetricDifference(other.p)}
+	}
+	p := set.p
+	other.Range(func(e E) bool {
+		if set.p.Contains(e) {
+			p = p.Without(e)
+		} else {
+			p = p.With(e)
+		}
+		return true
+	})
+	return &persistentSet[E]{p}
+}
+
+func (set *persistentSet[E]) Len() int {
+	return set.p.Len()
+}
+
+func (set *persistentSet[E]) Elems() []E {
+	return set.p.Elems()
+}
+
+func (set *persistentSet[E]) Range(fn func(v E) bool) {
+	set.p.Range(fn)
+}
+
+func (set *persistentSet[E]) All() iter.Seq[E] {
+	return set.p.All()
+}
+
+func (set *persistentSet[E]) Clone() Set[E] {
+	return &persistentSet[E]{set.p}
+}
+
+func hamtHash[E comparable](elem E) uint64 {
+	return hashComparable(hamtSeed, elem)
+}
+
+// A hamtNode is an immutable node of a hash array mapped trie.
+//
+// Each level of the trie consumes hamtBits of the hash. Bit i of the bitmap
+// is set if there's an entry for the hash fragment i and the entries are
+// ordered by hash fragment. Once all the bits of the hash have been consumed,
+// the node is a collision node and its entries are unordered leaves with
+// identical hashes.
+type hamtNode[E comparable] struct {
+	bitmap  uint32
+	size    int // Number of elements in the subtree.
+	entries []hamtEntry[E]
+}
+
+// A hamtEntry is either a subtree or a leaf containing a single element.
+type hamtEntry[E comparable] struct {
+	node *hamtNode[E] // Non-nil for subtrees.
+	hash uint64
+	elem E
+}
+
+func (e *hamtEntry[E]) size() int {
+	if e.node != nil {
+		return e.node.size
+	}
+	return 1
+}
+
+// newHamtNode returns a node with the given entries.
+// It returns nil if there are no entries.
+func newHamtNode[E comparable](bitmap uint32, entries []hamtEntry[E]) *hamtNode[E] {
+	if len(entries) == 0 {
+		return nil
+	}
+	n := &hamtNode[E]{bitmap: bitmap, entries: entries}
+	for i := range entries {
+		n.size += entries[i].size()
+	}
+	return n
+}
+
+// newHamtPair returns a subtree containing the two leaves.
+func newHamtPair[E comparable](a, b hamtEntry[E], shift uint) *hamtNode[E] {
+	if shift >= 64 {
+		return newHamtNode(0, []hamtEntry[E]{a, b})
+	}
+	fa, fb := fragment(a.hash, shift), fragment(b.hash, shift)
+	switch {
+	case fa == fb:
+		return newHamtNode(1<<fa, []hamtEntry[E]{{node: newHamtPair(a, b, shift+hamtBits)}})
+	case fa < fb:
+		return newHamtNode(1<<fa|1<<fb, []hamtEntry[E]{a, b})
+	default:
+		return newHamtNode(1<<fa|1<<fb, []hamtEntry[E]{b, a})
+	}
+}
+
+// toEntry returns an entry for the subtree, which is inlined if it's a single leaf.
+// It returns false if the subtree is empty.
+func (n *hamtNode[E]) toEntry() (hamtEntry[E], bool) {
+	switch {
+	case n == nil:
+		return hamtEntry[E]{}, false
+	case len(n.entries) == 1 && n.entries[0].node == nil:
+		return n.entries[0], true
+	default:
+		return hamtEntry[E]{node: n}, true
+	}
+}
+
+func fragment(hash uint64, shift uint) uint32 {
+	return uint32(hash>>shift) & hamtMask
+}
+
+// lookup returns the index of the entry for the hash fragment
+// and a value indicating if it exists.
+func (n *hamtNode[E]) lookup(frag uint32) (int, bool) {
+	bit := uint32(1) << frag
+	return bits.OnesCount32(n.bitmap & (bit - 1)), n.bitmap&bit != 0
+}
+
+func (n *hamtNode[E]) contains(hash uint64, shift uint, elem E) bool {
+	for n != nil {
+		if shift >= 64 {
+			return slices.ContainsFunc(n.entries, func(e hamtEntry[E]) bool { return e.elem == elem })
+		}
+		idx, ok := n.lookup(fragment(hash, shift))
+		if !ok {
+			return false
+		}
+		e := &n.entries[idx]
+		if e.node == nil {
+			return e.hash == hash && e.elem == elem
+		}
+		n, shift = e.node, shift+hamtBits
+	}
+	return false
+}
+
+// with returns a version of the subtree with the element
+// and a value indicating if it was added.
+func (n *hamtNode[E]) with(hash uint64, shift uint, elem E) (*hamtNode[E], bool) {
+	leaf := hamtEntry[E]{hash: hash, elem: elem}
+	if n == nil {
+		return newHamtNode(1<<fragment(hash, shift), []hamtEntry[E]{leaf}), true
+	}
+	if shift >= 64 {
+		if slices.ContainsFunc(n.entries, func(e hamtEntry[E]) bool { return e.elem == elem }) {
+			return n, false
+		}
+		return newHamtNode(0, append(slices.Clip(n.entries), leaf)), true
+	}
+	frag := fragment(hash, shift)
+	idx, ok := n.lookup(frag)
+	if !ok {
+		return newHamtNode(n.bitmap|1<<frag, slices.Insert(slices.Clip(n.entries), idx, leaf)), true
+	}
+	e := n.entries[idx]
+	switch {
+	case e.node != nil:
+		child, added := e.node.with(hash, shift+hamtBits, elem)
+		if !added {
+			return n, false
+		}
+		e = hamtEntry[E]{node: child}
+	case e.hash == hash && e.elem == elem:
+		return n, false
+	default:
+		e = hamtEntry[E]{node: newHamtPair(e, leaf, shift+hamtBits)}
+	}
+	entries := slices.Clone(n.entries)
+	entries[idx] = e
+	return newHamtNode(n.bitmap, entries), true
+}
+
+// without returns a version of the subtree without the element
+// and a value indicating if it was removed.
+func (n *hamtNode[E]) without(hash uint64, shift uint, elem E) (*hamtNode[E], bool) {
+	if n == nil {
+		return nil, false
+	}
+	if shift >= 64 {
+		idx := slices.IndexFunc(n.entries, func(e hamtEntry[E]) bool { return e.elem == elem })
+		if idx < 0 {
+			return n, false
+		}
+		return newHamtNode(0, slices.Delete(slices.Clone(n.entries), idx, idx+1)), true
+	}
+	frag := fragment(hash, shift)
+	idx, ok := n.lookup(frag)
+	if !ok {
+		return n, false
+	}
+	e := n.entries[idx]
+	if e.node == nil {
+		if e.hash != hash || e.elem != elem {
+			return n, false
+		}
+		return newHamtNode(n.bitmap&^(1<<frag), slices.Delete(slices.Clone(n.entries), idx, idx+1)), true
+	}
+	child, removed := e.node.without(hash, shift+hamtBits, elem)
+	if !removed {
+		return n, false
+	}
+	return n.replace(idx, frag, child), true
+}
+
+// replace returns a version of the node with the entry at index idx
+// for the hash fragment replaced by the subtree.
+func (n *hamtNode[E]) replace(idx int, frag uint32, child *hamtNode[E]) *hamtNode[E] {
+	e, ok := child.toEntry()
+	if !ok {
+		return newHamtNode(n.bitmap&^(1<<frag), slices.Delete(slices.Clone(n.entries), idx, idx+1))
+	}
+	entries := slices.Clone(n.entries)
+	entries[idx] = e
+	return newHamtNode(n.bitmap, entries)
+}
+
+// unionHamt returns the union of the subtrees, sharing their structure where possible.
+func unionHamt[E comparable](a, b *hamtNode[E], shift uint) *hamtNode[E] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || a == b:
+		return a
+	case shift >= 64:
+		n := a
+		for _, e := range b.entries {
+			n, _ = n.with(e.hash, shift, e.elem)
+		}
+		return n
+	}
+	entries := make([]hamtEntry[E], 0, bits.OnesCount32(a.bitmap|b.bitmap))
+	for bm := a.bitmap | b.bitmap; bm != 0; bm &= bm - 1 {
+		frag := uint32(bits.TrailingZeros32(bm))
+		ai, aok := a.lookup(frag)
+		bi, bok := b.lookup(frag)
+		switch {
+		case !bok:
+			entries = append(entries, a.entries[ai])
+		case !aok:
+			entries = append(entries, b.entries[bi])
+		default:
+			entries = append(entries, unionHamtEntries(a.entries[ai], b.entries[bi], shift+hamtBits))
+		}
+	}
+	return newHamtNode(a.bitmap|b.bitmap, entries)
+}
+
+func unionHamtEntries[E comparable](a, b hamtEntry[E], shift uint) hamtEntry[E] {
+	switch {
+	case a.node != nil && b.node != nil:
+		return hamtEntry[E]{node: unionHamt(a.node, b.node, shift)}
+	case a.node != nil:
+		n, _ := a.node.with(b.hash, shift, b.elem)
+		return hamtEntry[E]{node: n}
+	case b.node != nil:
+		n, _ := b.node.with(a.hash, shift, a.elem)
+		return hamtEntry[E]{node: n}
+	case a.hash == b.hash && a.elem == b.elem:
+		return a
+	default:
+		return hamtEntry[E]{node: newHamtPair(a, b, shift)}
+	}
+}
+
+// intersectHamt returns the intersection of the subtrees, sharing their structure where possible.
+func intersectHamt[E comparable](a, b *hamtNode[E], shift uint) *hamtNode[E] {
+	switch {
+	case a == nil || b == nil:
+		return nil
+	case a == b:
+		return a
+	case shift >= 64:
+		var entries []hamtEntry[E]
+		for _, e := range a.entries {
+			if b.contains(e.hash, shift, e.elem) {
+				entries = append(entries, e)
+			}
+		}
+		return newHamtNode(0, entries)
+	}
+	var bitmap uint32
+	var entries []hamtEntry[E]
+	for bm := a.bitmap & b.bitmap; bm != 0; bm &= bm - 1 {
+		frag := uint32(bits.TrailingZeros32(bm))
+		ai, _ := a.lookup(frag)
+		bi, _ := b.lookup(frag)
+		ae, be := a.entries[ai], b.entries[bi]
+		var e hamtEntry[E]
+		var ok bool
+		switch {
+		case ae.node != nil && be.node != nil:
+			e, ok = intersectHamt(ae.node, be.node, shift+hamtBits).toEntry()
+		case ae.node != nil:
+			e, ok = be, ae.node.contains(be.hash, shift+hamtBits, be.elem)
+		case be.node != nil:
+			e, ok = ae, be.node.contains(ae.hash, shift+hamtBits, ae.elem)
+		default:
+			e, ok = ae, ae.hash == be.hash && ae.elem == be.elem
+		}
+		if ok {
+			bitmap |= 1 << frag
+			entries = append(entries, e)
+		}
+	}
+	return newHamtNode(bitmap, entries)
+}
+
+// diffHamt returns the difference of the subtrees, sharing their structure where possible.
+func diffHamt[E comparable](a, b *hamtNode[E], shift uint) *hamtNode[E] {
+	switch {
+	case a == nil || a == b:
+		return nil
+	case b == nil:
+		return a
+	case shift >= 64:
+		var entries []hamtEntry[E]
+		for _, e := range a.entries {
+			if !b.contains(e.hash, shift, e.elem) {
+				entries = append(entries, e)
+			}
+		}
+		return newHamtNode(0, entries)
+	}
+	var bitmap uint32
+	var entries []hamtEntry[E]
+	for bm := a.bitmap; bm != 0; bm &= bm - 1 {
+		frag := uint32(bits.TrailingZeros32(bm))
+		ai, _ := a.lookup(frag)
+		ae := a.entries[ai]
+		bi, bok := b.lookup(frag)
+		e, ok := ae, true
+		if bok {
+			switch be := b.entries[bi]; {
+			case ae.node != nil && be.node != nil:
+				e, ok = diffHamt(ae.node, be.node, shift+hamtBits).toEntry()
+			case ae.node != nil:
+				n, _ := ae.node.without(be.hash, shift+hamtBits, be.elem)
+				e, ok = n.toEntry()
+			case be.node != nil:
+				ok = !be.node.contains(ae.hash, shift+hamtBits, ae.elem)
+			default:
+				ok = ae.hash != be.hash || ae.elem != be.elem
+			}
+		}
+		if ok {
+			bitmap |= 1 << frag
+			entries = append(entries, e)
+		}
+	}
+	return newHamtNode(bitmap, entries)
+}
+
+// ascend calls fn with each element of the subtree until
+// there are no elements remaining or fn returns false.
+func (n *hamtNode[E]) ascend(fn func(E) bool) bool {
+	for i := range n.entries {
+		e := &n.entries[i]
+		if e.node != nil {
+			if !e.node.ascend(fn) {
+				return false
+			}
+		} else if !fn(e.elem) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestPersistentVersions(t *testing.T) {
+	var empty Persistent[int]
+	if got := empty.Len(); got != 0 {
+		t.Fatalf("empty.Len(); got: %v; want: 0", got)
+	}
+	v1 := NewPersistent(1, 2, 3)
+	v2 := v1.With(4)
+	v3 := v2.Without(1)
+	for _, tt := range []struct {
+		name string
+		set  Persistent[int]
+		want []int
+	}{
+		{"v1", v1, []int{1, 2, 3}},
+		{"v2", v2, []int{1, 2, 3, 4}},
+		{"v3", v3, []int{2, 3, 4}},
+		{"v1.Union(v3)", v1.Union(v3), []int{1, 2, 3, 4}},
+		{"v1.Intersection(v3)", v1.Intersection(v3), []int{2, 3}},
+		{"v1.Difference(v3)", v1.Difference(v3), []int{1}},
+		{"v1.SymmetricDifference(v3)", v1.SymmetricDifference(v3), []int{1, 4}},
+		{"v1.AsSet()", func() Persistent[int] { s := v1.AsSet(); s.Insert(5); return v1 }(), []int{1, 2, 3}},
+	} {
+		if got := slices.Sorted(tt.set.All()); !slices.Equal(got, tt.want) {
+			t.Errorf("%s; got: %v; want: %v", tt.name, got, tt.want)
+		}
+		if got, want := tt.set.Len(), len(tt.want); got != want {
+			t.Errorf("%s.Len(); got: %v; want: %v", tt.name, got, want)
+		}
+	}
+}
+
+func TestPersistentSharing(t *testing.T) {
+	var elems []int
+	for i := range 10000 {
+		elems = append(elems, i)
+	}
+	a := NewPersistent(elems...)
+	b := a.With(-1)
+	// Only the nodes along the path to the new element are copied.
+	shared := 0
+	for i := range a.root.entries {
+		if a.root.entries[i].node != nil && a.root.entries[i].node == b.root.entries[i].node {
+			shared++
+		}
+	}
+	if want := len(a.root.entries) - 1; shared != want {
+		t.Fatalf("shared subtrees; got: %v; want: %v", shared, want)
+	}
+	if u := a.Union(a); u.root != a.root {
+		t.Fatalf("a.Union(a) didn't share its root")
+	}
+	if got, want := b.Difference(a).Elems(), []int{-1}; !slices.Equal(got, want) {
+		t.Fatalf("b.Difference(a).Elems(); got: %v; want: %v", got, want)
+	}
+}
+
+func TestPersistentCollisions(t *testing.T) {
+	// Use identical hashes to force collision nodes.
+	const h = 0xdeadbeefcafef00d
+	var root *hamtNode[int]
+	for i := range 5 {
+		var added bool
+		if root, added = root.with(h, 0, i); !added {
+			t.Fatalf("with(%v); got: false; want: true", i)
+		}
+	}
+	root, _ = root.with(h^1, 0, 100)
+	if got, want := root.size, 6; got != want {
+		t.Fatalf("root.size; got: %v; want: %v", got, want)
+	}
+	for i := range 5 {
+		if !root.contains(h, 0, i) {
+			t.Fatalf("contains(%v); got: false; want: true", i)
+		}
+	}
+	if root.contains(h, 0, 5) {
+		t.Fatalf("contains(5); got: true; want: false")
+	}
+	other, _ := (*hamtNode[int])(nil).with(h, 0, 2)
+	other, _ = other.with(h, 0, 7)
+	for _, tt := range []struct {
+		name string
+		node *hamtNode[int]
+		want []int
+	}{
+		{"union", unionHamt(root, other, 0), []int{0, 1, 2, 3, 4, 7, 100}},
+		{"intersect", intersectHamt(root, other, 0), []int{2}},
+		{"diff", diffHamt(root, other, 0), []int{0, 1, 3, 4, 100}},
+	} {
+		got := slices.SortedFunc(Persistent[int]{tt.node}.All(), cmp.Compare[int])
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s; got: %v; want: %v", tt.name, got, tt.want)
+		}
+	}
+	for i := range 5 {
+		root, _ = root.without(h, 0, i)
+	}
+	root, _ = root.without(h^1, 0, 100)
+	if root != nil {
+		t.Fatalf("root after removing all elements; got: %v elements; want: nil", root.size)
+	}
+}
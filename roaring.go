@@ -0,0 +1,1007 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// NewRoaring returns a sorted set initialized with the given elements.
+// It's backed by a roaring bitmap, which partitions the elements by their high bits
+// into containers of up to 65536 elements, each of which is stored as a sorted array,
+// a bitmap, or a list of runs, whichever is the most compact. It's best suited for
+// large sets of sparse or clustered integers.
+// Set operations between roaring bitmaps operate on whole containers at a time,
+// and it keeps a count of the elements in each container so that Rank, At, and
+// CountRange take O(log n) time in its number of containers.
+func NewRoaring[E ~uint32 | ~uint64](elems ...E) Sorted[E] {
+	return newRoaring(slices.Sorted(slices.Values(elems)))
+}
+
+// newRoaring returns a roaring bitmap built from the given elements, which must be sorted.
+func newRoaring[E ~uint32 | ~uint64](elems []E) *roaring[E] {
+	set := &roaring[E]{}
+	var values []uint16
+	for len(elems) > 0 {
+		key := uint64(elems[0]) >> 16
+		values = values[:0]
+		for len(elems) > 0 && uint64(elems[0])>>16 == key {
+			if v := uint16(elems[0]); len(values) == 0 || values[len(values)-1] != v {
+				values = append(values, v)
+			}
+			elems = elems[1:]
+		}
+		set.keys = append(set.keys, key)
+		set.containers = append(set.containers, newContainer(values))
+	}
+	set.recount()
+	return set
+}
+
+type roaring[E ~uint32 | ~uint64] struct {
+	keys       []uint64    // High bits of the elements in ascending order.
+	containers []container // Low bits of the elements for each key.
+	counts     fenwick     // Number of elements in each container.
+}
+
+func (set *roaring[E]) Contains(elem E) bool {
+	idx, found := set.find(uint64(elem) >> 16)
+	return found && set.containers[idx].contains(uint16(elem))
+}
+
+func (set *roaring[E]) ContainsAll(elems ...E) bool {
+	for _, e := range elems {
+		if !set.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *roaring[E]) ContainsSet(other Set[E]) bool {
+	if other, ok := other.(*roaring[E]); ok {
+		for i, key := range other.keys {
+			idx, found := set.find(key)
+			if !found {
+				return false
+			}
+			c := other.containers[i]
+			if and(c, set.containers[idx]).card() != c.card() {
+				return false
+			}
+		}
+		return true
+	}
+	ok := true
+	other.Range(func(e E) bool {
+		ok = set.Contains(e)
+		return ok
+	})
+	return ok
+}
+
+func (set *roaring[E]) isDisjoint(other Set[E]) (disjoint, ok bool) {
+	o, ok := other.(*roaring[E])
+	if !ok {
+		return false, false
+	}
+	for i, key := range o.keys {
+		if idx, found := set.find(key); found && and(set.containers[idx], o.containers[i]).card() > 0 {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+func (set *roaring[E]) Insert(elem E) {
+	key, v := uint64(elem)>>16, uint16(elem)
+	idx, found := set.find(key)
+	if !found {
+		set.keys = slices.Insert(set.keys, idx, key)
+		set.containers = slices.Insert(set.containers, idx, container(&arrayContainer{values: []uint16{v}}))
+		set.recount()
+		return
+	}
+	c := set.containers[idx]
+	n := c.card()
+	set.containers[idx] = c.add(v)
+	set.counts.add(idx, set.containers[idx].card()-n)
+}
+
+func (set *roaring[E]) InsertAll(elems ...E) {
+	set.InsertSet(NewRoaring(elems...))
+}
+
+func (set *roaring[E]) InsertSet(other Set[E]) {
+	if set == other {
+		return
+	}
+	o, ok := other.(*roaring[E])
+	if !ok {
+		o = newRoaring(slices.Sorted(other.All()))
+	}
+	set.reset(set.merge(o, or, true, true, false))
+}
+
+func (set *roaring[E]) insertSeq(seq iter.Seq[E]) {
+	set.InsertSet(newRoaring(slices.Sorted(seq)))
+}
+
+func (set *roaring[E]) Remove(elem E) {
+	idx, found := set.find(uint64(elem) >> 16)
+	if !found {
+		return
+	}
+	c := set.containers[idx]
+	n := c.card()
+	if c = c.remove(uint16(elem)); c.card() == 0 {
+		set.keys = slices.Delete(set.keys, idx, idx+1)
+		set.containers = slices.Delete(set.containers, idx, idx+1)
+		set.recount()
+		return
+	}
+	set.containers[idx] = c
+	set.counts.add(idx, c.card()-n)
+}
+
+func (set *roaring[E]) RemoveAll(elems ...E) {
+	set.RemoveSet(NewRoaring(elems...))
+}
+
+func (set *roaring[E]) RemoveSet(other Set[E]) {
+	o, ok := other.(*roaring[E])
+	if !ok {
+		o = newRoaring(slices.Sorted(other.All()))
+	}
+	set.reset(set.merge(o, andNot, true, false, false))
+}
+
+func (set *roaring[E]) Intersection(other Set[E]) Set[E] {
+	if other, ok := other.(*roaring[E]); ok {
+		s := &roaring[E]{}
+		s.reset(set.merge(other, and, false, false, true))
+		return s
+	}
+	var elems []E
+	for e := range set.All() {
+		if other.Contains(e) {
+			elems = append(elems, e)
+		}
+	}
+	return newRoaring(elems)
+}
+
+func (set *roaring[E]) Union(other Set[E]) Set[E] {
+	o, ok := other.(*roaring[E])
+	if !ok {
+		o = newRoaring(slices.Sorted(other.All()))
+	}
+	s := &roaring[E]{}
+	s.reset(set.merge(o, or, true, true, true))
+	return s
+}
+
+func (set *roaring[E]) Difference(other Set[E]) Set[E] {
+	if other, ok := other.(*roaring[E]); ok {
+		s := &roaring[E]{}
+		s.reset(set.merge(other, andNot, true, false, true))
+		return s
+	}
+	var elems []E
+	for e := range set.All() {
+		if !other.Contains(e) {
+			elems = append(elems, e)
+		}
+	}
+	return newRoaring(elems)
+}
+
+func (set *roaring[E]) SymmetricDifference(other Set[E]) Set[E] {
+	o, ok := other.(*roaring[E])
+	if !ok {
+		o = newRoaring(slices.Sorted(other.All()))
+	}
+	s := &roaring[E]{}
+	s.reset(set.merge(o, xor, true, true, true))
+	return s
+}
+
+func (set *roaring[E]) Len() int {
+	return set.counts.sum(len(set.counts))
+}
+
+func (set *roaring[E]) Elems() []E {
+	elems := make([]E, 0, set.Len())
+	for e := range set.All() {
+		elems = append(elems, e)
+	}
+	return elems
+}
+
+func (set *roaring[E]) Range(fn func(v E) bool) {
+	for e := range set.All() {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
+func (set *roaring[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i, c := range set.containers {
+			hi := set.keys[i] << 16
+			if !c.ascend(func(v uint16) bool { return yield(E(hi | uint64(v))) }) {
+				return
+			}
+		}
+	}
+}
+
+func (set *roaring[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := len(set.containers) - 1; i >= 0; i-- {
+			hi := set.keys[i] << 16
+			if !set.containers[i].descend(func(v uint16) bool { return yield(E(hi | uint64(v))) }) {
+				return
+			}
+		}
+	}
+}
+
+func (set *roaring[E]) Min() (E, bool) {
+	if len(set.containers) == 0 {
+		return 0, false
+	}
+	v, _ := set.containers[0].next(0)
+	return set.elem(0, v), true
+}
+
+func (set *roaring[E]) Max() (E, bool) {
+	n := len(set.containers)
+	if n == 0 {
+		return 0, false
+	}
+	v, _ := set.containers[n-1].prev(1<<16 - 1)
+	return set.elem(n-1, v), true
+}
+
+func (set *roaring[E]) Floor(elem E) (E, bool) {
+	idx, found := set.find(uint64(elem) >> 16)
+	if found {
+		if v, ok := set.containers[idx].prev(uint16(elem)); ok {
+			return set.elem(idx, v), true
+		}
+	}
+	if idx == 0 {
+		return 0, false
+	}
+	v, _ := set.containers[idx-1].prev(1<<16 - 1)
+	return set.elem(idx-1, v), true
+}
+
+func (set *roaring[E]) Ceiling(elem E) (E, bool) {
+	idx, found := set.find(uint64(elem) >> 16)
+	if found {
+		if v, ok := set.containers[idx].next(uint16(elem)); ok {
+			return set.elem(idx, v), true
+		}
+		idx++
+	}
+	if idx == len(set.containers) {
+		return 0, false
+	}
+	v, _ := set.containers[idx].next(0)
+	return set.elem(idx, v), true
+}
+
+func (set *roaring[E]) Lower(elem E) (E, bool) {
+	if elem == 0 {
+		return 0, false
+	}
+	return set.Floor(elem - 1)
+}
+
+func (set *roaring[E]) Higher(elem E) (E, bool) {
+	if elem+1 == 0 {
+		return 0, false
+	}
+	return set.Ceiling(elem + 1)
+}
+
+func (set *roaring[E]) SubSet(lo, hi E, bounds Bounds) Sorted[E] {
+	i, j := boundsIndex(set, lo, hi, bounds)
+	return set.slice(i, j)
+}
+
+func (set *roaring[E]) HeadSet(hi E) Sorted[E] {
+	return set.slice(0, set.lowerBound(hi))
+}
+
+func (set *roaring[E]) TailSet(lo E) Sorted[E] {
+	return set.slice(set.lowerBound(lo), set.Len())
+}
+
+func (set *roaring[E]) CountRange(lo, hi E) int {
+	i, j := boundsIndex(set, lo, hi, IncludeLo)
+	return j - i
+}
+
+func (set *roaring[E]) Rank(elem E) int {
+	return set.lowerBound(elem)
+}
+
+func (set *roaring[E]) At(i int) E {
+	if i >= 0 {
+		if k, i := set.counts.search(i); k < len(set.containers) {
+			return set.elem(k, set.containers[k].selectAt(i))
+		}
+	}
+	panic("sets: index out of range")
+}
+
+func (set *roaring[E]) Slice(i, j int) Sorted[E] {
+	if i < 0 || j > set.Len() || i > j {
+		panic("sets: slice bounds out of range")
+	}
+	return set.slice(i, j)
+}
+
+// slice returns a new set with the elements at indices [i, j).
+func (set *roaring[E]) slice(i, j int) *roaring[E] {
+	s := &roaring[E]{}
+	off := 0
+	for k, c := range set.containers {
+		n := c.card()
+		lo, hi := max(i-off, 0), min(j-off, n)
+		off += n
+		switch {
+		case lo >= hi:
+			continue
+		case lo == 0 && hi == n:
+			c = c.clone()
+		default:
+			values := make([]uint16, 0, hi-lo)
+			for x := lo; x < hi; x++ {
+				values = append(values, c.selectAt(x))
+			}
+			c = newContainer(values)
+		}
+		s.keys = append(s.keys, set.keys[k])
+		s.containers = append(s.containers, c)
+	}
+	s.recount()
+	return s
+}
+
+func (set *roaring[E]) Clone() Set[E] {
+	s := &roaring[E]{
+		keys:       slices.Clone(set.keys),
+		containers: make([]container, len(set.containers)),
+		counts:     slices.Clone(set.counts),
+	}
+	for i, c := range set.containers {
+		s.containers[i] = c.clone()
+	}
+	return s
+}
+
+func (set *roaring[E]) search(elem E) (int, bool) {
+	return set.lowerBound(elem), set.Contains(elem)
+}
+
+// lowerBound returns the number of elements less than elem.
+func (set *roaring[E]) lowerBound(elem E) int {
+	idx, found := set.find(uint64(elem) >> 16)
+	n := set.counts.sum(idx)
+	if found {
+		n += set.containers[idx].rank(uint16(elem))
+	}
+	return n
+}
+
+// upperBound returns the number of elements less than or equal to elem.
+func (set *roaring[E]) upperBound(elem E) int {
+	if elem+1 == 0 {
+		return set.Len()
+	}
+	return set.lowerBound(elem + 1)
+}
+
+// find returns the index of the container for the key
+// and a value indicating if it exists.
+func (set *roaring[E]) find(key uint64) (int, bool) {
+	idx := sort.Search(len(set.keys), func(i int) bool { return key <= set.keys[i] })
+	return idx, idx < len(set.keys) && set.keys[idx] == key
+}
+
+// reset replaces the keys and containers.
+func (set *roaring[E]) reset(keys []uint64, containers []container) {
+	set.keys, set.containers = keys, containers
+	set.recount()
+}
+
+// recount rebuilds the counts after the containers are added or removed.
+func (set *roaring[E]) recount() {
+	set.counts.reset(len(set.containers), func(i int) int { return set.containers[i].card() })
+}
+
+func (set *roaring[E]) elem(idx int, v uint16) E {
+	return E(set.keys[idx]<<16 | uint64(v))
+}
+
+// merge combines the containers of the set and other with the given operation.
+// Containers that are only in the set or only in other are kept as indicated.
+// Containers from other are always cloned, while containers from the set are only
+// cloned if clone is true.
+func (set *roaring[E]) merge(other *roaring[E], op func(a, b container) container, keepA, keepB, clone bool) ([]uint64, []container) {
+	var keys []uint64
+	var containers []container
+	add := func(key uint64, c container) {
+		if c.card() > 0 {
+			keys = append(keys, key)
+			containers = append(containers, c)
+		}
+	}
+	a, b := 0, 0
+	for a < len(set.keys) || b < len(other.keys) {
+		switch {
+		case b == len(other.keys) || (a < len(set.keys) && set.keys[a] < other.keys[b]):
+			if keepA {
+				c := set.containers[a]
+				if clone {
+					c = c.clone()
+				}
+				add(set.keys[a], c)
+			}
+			a++
+		case a == len(set.keys) || set.keys[a] > other.keys[b]:
+			if keepB {
+				add(other.keys[b], other.containers[b].clone())
+			}
+			b++
+		default:
+			add(set.keys[a], op(set.containers[a], other.containers[b]))
+			a++
+			b++
+		}
+	}
+	return keys, containers
+}
+
+const (
+	arrayMaxCard = 4096
+	bitmapWords  = 1 << 16 / 64
+)
+
+// A container holds the low 16 bits of the elements that share the same high bits.
+// Containers may be modified in place by add and remove, which return
+// the container to use in its place. All other operations return new containers.
+type container interface {
+	contains(v uint16) bool
+	add(v uint16) container
+	remove(v uint16) container
+	card() int
+	// numRuns returns the number of runs of consecutive values.
+	numRuns() int
+	// rank returns the number of values less than v.
+	rank(v uint16) int
+	// selectAt returns the value at index i.
+	selectAt(i int) uint16
+	// next returns the least value greater than or equal to v.
+	next(v uint16) (uint16, bool)
+	// prev returns the greatest value less than or equal to v.
+	prev(v uint16) (uint16, bool)
+	ascend(fn func(uint16) bool) bool
+	descend(fn func(uint16) bool) bool
+	// toBitmap returns a bitmap with the same values,
+	// which may be the container itself.
+	toBitmap() *bitmapContainer
+	clone() container
+}
+
+// newContainer returns the most compact container for the values,
+// which must be sorted and unique.
+func newContainer(values []uint16) container {
+	return optimize(&arrayContainer{values: slices.Clone(values)})
+}
+
+// optimize returns the most compact container with the same values.
+func optimize(c container) container {
+	card := c.card()
+	runBytes := 2 + 4*c.numRuns()
+	arrayBytes := 2 * card
+	bitmapBytes := 8 * bitmapWords
+	switch {
+	case runBytes < min(arrayBytes, bitmapBytes):
+		if c, ok := c.(*runContainer); ok {
+			return c
+		}
+		return toRun(c)
+	case card <= arrayMaxCard:
+		if c, ok := c.(*arrayContainer); ok {
+			return c
+		}
+		return toArray(c)
+	default:
+		return c.toBitmap()
+	}
+}
+
+func toArray(c container) *arrayContainer {
+	a := &arrayContainer{values: make([]uint16, 0, c.card())}
+	c.ascend(func(v uint16) bool {
+		a.values = append(a.values, v)
+		return true
+	})
+	return a
+}
+
+func toRun(c container) *runContainer {
+	r := &runContainer{runs: make([]interval16, 0, c.numRuns()), n: c.card()}
+	c.ascend(func(v uint16) bool {
+		if n := len(r.runs); n > 0 && r.runs[n-1].last+1 == v {
+			r.runs[n-1].last = v
+		} else {
+			r.runs = append(r.runs, interval16{v, v})
+		}
+		return true
+	})
+	return r
+}
+
+// and returns the intersection of the containers.
+func and(a, b container) container {
+	if _, ok := b.(*arrayContainer); ok {
+		a, b = b, a
+	}
+	if a, ok := a.(*arrayContainer); ok {
+		return optimize(a.filter(b, true))
+	}
+	x, y := a.toBitmap(), b.toBitmap()
+	s := &bitmapContainer{}
+	for i := range s.words {
+		s.words[i] = x.words[i] & y.words[i]
+	}
+	return optimize(s.count())
+}
+
+// or returns the union of the containers.
+func or(a, b container) container {
+	x, xok := a.(*arrayContainer)
+	y, yok := b.(*arrayContainer)
+	if xok && yok && len(x.values)+len(y.values) <= arrayMaxCard {
+		s := &arrayContainer{values: make([]uint16, 0, len(x.values)+len(y.values))}
+		i, k := 0, 0
+		for i < len(x.values) && k < len(y.values) {
+			switch xv, yv := x.values[i], y.values[k]; {
+			case xv < yv:
+				s.values = append(s.values, xv)
+				i++
+			case xv > yv:
+				s.values = append(s.values, yv)
+				k++
+			default:
+				s.values = append(s.values, xv)
+				i++
+				k++
+			}
+		}
+		s.values = append(s.values, x.values[i:]...)
+		s.values = append(s.values, y.values[k:]...)
+		return optimize(s)
+	}
+	xb, yb := a.toBitmap(), b.toBitmap()
+	s := &bitmapContainer{}
+	for i := range s.words {
+		s.words[i] = xb.words[i] | yb.words[i]
+	}
+	return optimize(s.count())
+}
+
+// andNot returns the difference of the containers.
+func andNot(a, b container) container {
+	if a, ok := a.(*arrayContainer); ok {
+		return optimize(a.filter(b, false))
+	}
+	x, y := a.toBitmap(), b.toBitmap()
+	s := &bitmapContainer{}
+	for i := range s.words {
+		s.words[i] = x.words[i] &^ y.words[i]
+	}
+	return optimize(s.count())
+}
+
+// xor returns the symmetric difference of the containers.
+func xor(a, b container) container {
+	x, y := a.toBitmap(), b.toBitmap()
+	s := &bitmapContainer{}
+	for i := range s.words {
+		s.words[i] = x.words[i] ^ y.words[i]
+	}
+	return optimize(s.count())
+}
+
+// An arrayContainer holds up to arrayMaxCard values in a sorted list.
+type arrayContainer struct {
+	values []uint16
+}
+
+func (c *arrayContainer) contains(v uint16) bool {
+	_, found := slices.BinarySearch(c.values, v)
+	return found
+}
+
+func (c *arrayContainer) add(v uint16) container {
+	idx, found := slices.BinarySearch(c.values, v)
+	if found {
+		return c
+	}
+	if len(c.values) == arrayMaxCard {
+		return c.toBitmap().add(v)
+	}
+	c.values = slices.Insert(c.values, idx, v)
+	return c
+}
+
+func (c *arrayContainer) remove(v uint16) container {
+	if idx, found := slices.BinarySearch(c.values, v); found {
+		c.values = slices.Delete(c.values, idx, idx+1)
+	}
+	return c
+}
+
+func (c *arrayContainer) card() int {
+	return len(c.values)
+}
+
+func (c *arrayContainer) numRuns() int {
+	n := 0
+	for i, v := range c.values {
+		if i == 0 || c.values[i-1]+1 != v {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *arrayContainer) rank(v uint16) int {
+	idx, _ := slices.BinarySearch(c.values, v)
+	return idx
+}
+
+func (c *arrayContainer) selectAt(i int) uint16 {
+	return c.values[i]
+}
+
+func (c *arrayContainer) next(v uint16) (uint16, bool) {
+	idx, _ := slices.BinarySearch(c.values, v)
+	return index(c.values, idx)
+}
+
+func (c *arrayContainer) prev(v uint16) (uint16, bool) {
+	idx, found := slices.BinarySearch(c.values, v)
+	if found {
+		return v, true
+	}
+	return index(c.values, idx-1)
+}
+
+func (c *arrayContainer) ascend(fn func(uint16) bool) bool {
+	for _, v := range c.values {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *arrayContainer) descend(fn func(uint16) bool) bool {
+	for i := len(c.values) - 1; i >= 0; i-- {
+		if !fn(c.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *arrayContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{n: len(c.values)}
+	for _, v := range c.values {
+		b.words[v/64] |= 1 << (v % 64)
+	}
+	return b
+}
+
+func (c *arrayContainer) clone() container {
+	return &arrayContainer{values: slices.Clone(c.values)}
+}
+
+// filter returns a new container with the values that are or aren't in other.
+func (c *arrayContainer) filter(other container, in bool) *arrayContainer {
+	s := &arrayContainer{}
+	for _, v := range c.values {
+		if other.contains(v) == in {
+			s.values = append(s.values, v)
+		}
+	}
+	return s
+}
+
+// A bitmapContainer holds values as bits.
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	n     int // Cardinality.
+}
+
+func (c *bitmapContainer) contains(v uint16) bool {
+	return c.words[v/64]&(1<<(v%64)) != 0
+}
+
+func (c *bitmapContainer) add(v uint16) container {
+	if !c.contains(v) {
+		c.words[v/64] |= 1 << (v % 64)
+		c.n++
+	}
+	return c
+}
+
+func (c *bitmapContainer) remove(v uint16) container {
+	if !c.contains(v) {
+		return c
+	}
+	c.words[v/64] &^= 1 << (v % 64)
+	if c.n--; c.n <= arrayMaxCard {
+		return toArray(c)
+	}
+	return c
+}
+
+func (c *bitmapContainer) card() int {
+	return c.n
+}
+
+func (c *bitmapContainer) numRuns() int {
+	n := 0
+	var carry uint64 // Top bit of the previous word.
+	for _, w := range c.words {
+		n += bits.OnesCount64(w &^ (w<<1 | carry)) // Count the first bit of each run.
+		carry = w >> 63
+	}
+	return n
+}
+
+func (c *bitmapContainer) rank(v uint16) int {
+	n := 0
+	for _, w := range c.words[:v/64] {
+		n += bits.OnesCount64(w)
+	}
+	return n + bits.OnesCount64(c.words[v/64]&(1<<(v%64)-1))
+}
+
+func (c *bitmapContainer) selectAt(i int) uint16 {
+	for k, w := range c.words {
+		if n := bits.OnesCount64(w); i >= n {
+			i -= n
+			continue
+		}
+		for ; i > 0; i-- {
+			w &= w - 1
+		}
+		return uint16(k*64 + bits.TrailingZeros64(w))
+	}
+	panic("sets: index out of range")
+}
+
+func (c *bitmapContainer) next(v uint16) (uint16, bool) {
+	w := c.words[v/64] &^ (1<<(v%64) - 1)
+	for k := int(v / 64); ; {
+		if w != 0 {
+			return uint16(k*64 + bits.TrailingZeros64(w)), true
+		}
+		if k++; k == bitmapWords {
+			return 0, false
+		}
+		w = c.words[k]
+	}
+}
+
+func (c *bitmapContainer) prev(v uint16) (uint16, bool) {
+	w := c.words[v/64] & (^uint64(0) >> (63 - v%64))
+	for k := int(v / 64); ; {
+		if w != 0 {
+			return uint16(k*64 + 63 - bits.LeadingZeros64(w)), true
+		}
+		if k--; k < 0 {
+			return 0, false
+		}
+		w = c.words[k]
+	}
+}
+
+func (c *bitmapContainer) ascend(fn func(uint16) bool) bool {
+	for k, w := range c.words {
+		for ; w != 0; w &= w - 1 {
+			if !fn(uint16(k*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) descend(fn func(uint16) bool) bool {
+	for k := bitmapWords - 1; k >= 0; k-- {
+		for w := c.words[k]; w != 0; {
+			i := 63 - bits.LeadingZeros64(w)
+			if !fn(uint16(k*64 + i)) {
+				return false
+			}
+			w &^= 1 << i
+		}
+	}
+	return true
+}
+
+func (c *bitmapContainer) toBitmap() *bitmapContainer {
+	return c
+}
+
+func (c *bitmapContainer) clone() container {
+	s := *c
+	return &s
+}
+
+// count updates the cardinality and returns the container.
+func (c *bitmapContainer) count() *bitmapContainer {
+	c.n = 0
+	for _, w := range c.words {
+		c.n += bits.OnesCount64(w)
+	}
+	return c
+}
+
+// A runContainer holds values as a sorted list of disjoint, non-adjacent intervals.
+// Runs are never modified in place, so the cardinality is computed once.
+type runContainer struct {
+	runs []interval16
+	n    int // Cardinality.
+}
+
+// An interval16 is a closed interval [start, last].
+type interval16 struct {
+	start, last uint16
+}
+
+// find returns the index of the last run starting at or before v,
+// which is -1 if there's no such run.
+func (c *runContainer) find(v uint16) int {
+	return sort.Search(len(c.runs), func(i int) bool { return v < c.runs[i].start }) - 1
+}
+
+func (c *runContainer) contains(v uint16) bool {
+	i := c.find(v)
+	return i >= 0 && v <= c.runs[i].last
+}
+
+func (c *runContainer) add(v uint16) container {
+	if c.contains(v) {
+		return c
+	}
+	return c.unpack().add(v)
+}
+
+func (c *runContainer) remove(v uint16) container {
+	if !c.contains(v) {
+		return c
+	}
+	return c.unpack().remove(v)
+}
+
+// unpack returns an array or bitmap container with the same values.
+func (c *runContainer) unpack() container {
+	if c.card() <= arrayMaxCard {
+		return toArray(c)
+	}
+	return c.toBitmap()
+}
+
+func (c *runContainer) card() int {
+	return c.n
+}
+
+func (c *runContainer) numRuns() int {
+	return len(c.runs)
+}
+
+func (c *runContainer) rank(v uint16) int {
+	n := 0
+	for _, r := range c.runs {
+		switch {
+		case v > r.last:
+			n += int(r.last-r.start) + 1
+		case v > r.start:
+			return n + int(v-r.start)
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+func (c *runContainer) selectAt(i int) uint16 {
+	for _, r := range c.runs {
+		if n := int(r.last-r.start) + 1; i >= n {
+			i -= n
+			continue
+		}
+		return r.start + uint16(i)
+	}
+	panic("sets: index out of range")
+}
+
+func (c *runContainer) next(v uint16) (uint16, bool) {
+	i := c.find(v)
+	if i >= 0 && v <= c.runs[i].last {
+		return v, true
+	}
+	if i+1 < len(c.runs) {
+		return c.runs[i+1].start, true
+	}
+	return 0, false
+}
+
+func (c *runContainer) prev(v uint16) (uint16, bool) {
+	i := c.find(v)
+	if i < 0 {
+		return 0, false
+	}
+	return min(v, c.runs[i].last), true
+}
+
+func (c *runContainer) ascend(fn func(uint16) bool) bool {
+	for _, r := range c.runs {
+		for v := int(r.start); v <= int(r.last); v++ {
+			if !fn(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *runContainer) descend(fn func(uint16) bool) bool {
+	for i := len(c.runs) - 1; i >= 0; i-- {
+		r := c.runs[i]
+		for v := int(r.last); v >= int(r.start); v-- {
+			if !fn(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *runContainer) toBitmap() *bitmapContainer {
+	b := &bitmapContainer{}
+	for _, r := range c.runs {
+		for v := int(r.start); v <= int(r.last); {
+			// Set the bits from v to the end of the run or word, whichever comes first.
+			k, lo := v/64, v%64
+			hi := min(int(r.last)-k*64, 63)
+			b.words[k] |= (^uint64(0) >> (63 - hi)) &^ (1<<lo - 1)
+			v = k*64 + hi + 1
+		}
+	}
+	return b.count()
+}
+
+func (c *runContainer) clone() container {
+	return &runContainer{runs: slices.Clone(c.runs), n: c.n}
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRoaringSets(t *testing.T) {
+	newSetTester(t, []uint32{
+		0, 1, 2, 1<<16 - 1, 1 << 16, 1<<16 + 1, 1 << 20, 1<<20 + 7,
+		1 << 24, 1 << 28, 1 << 31, 1<<31 + 1, math.MaxUint32 - 2, math.MaxUint32 - 1, math.MaxUint32, 12345,
+	}, []*setType[uint32]{
+		{
+			name:    "table",
+			newSet:  New[uint32],
+			cmpFn:   cmp.Compare[uint32],
+			eqFn:    equal[uint32],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "ordered",
+			newSet:  func(elems ...uint32) Set[uint32] { return NewSorted(elems...) },
+			cmpFn:   cmp.Compare[uint32],
+			eqFn:    equal[uint32],
+			sorted:  true,
+			uniqCmp: true,
+		},
+		{
+			name:    "roaring",
+			newSet:  func(elems ...uint32) Set[uint32] { return NewRoaring(elems...) },
+			cmpFn:   cmp.Compare[uint32],
+			eqFn:    equal[uint32],
+			sorted:  true,
+			uniqCmp: true,
+		},
+		{
+			name:   "external",
+			newSet: func(elems ...uint32) Set[uint32] { return &externalSet[uint32]{New(elems...)} },
+			skip:   true,
+		},
+	}).test(t)
+}
+
+func TestRoaringContainers(t *testing.T) {
+	kind := func(set Sorted[uint32]) string {
+		switch set.(*roaring[uint32]).containers[0].(type) {
+		case *arrayContainer:
+			return "array"
+		case *bitmapContainer:
+			return "bitmap"
+		case *runContainer:
+			return "run"
+		}
+		return "unknown"
+	}
+	var sparse, dense []uint32
+	for i := range uint32(arrayMaxCard) {
+		sparse = append(sparse, 2*i)
+		dense = append(dense, i)
+	}
+
+	set := NewRoaring(sparse...)
+	if got := kind(set); got != "array" {
+		t.Errorf("sparse container; got: %v; want: array", got)
+	}
+	set.Insert(1)
+	if got := kind(set); got != "bitmap" {
+		t.Errorf("sparse container after insert; got: %v; want: bitmap", got)
+	}
+	set.Remove(1)
+	if got := kind(set); got != "array" {
+		t.Errorf("sparse container after remove; got: %v; want: array", got)
+	}
+	if got := set.Elems(); !slices.Equal(got, sparse) {
+		t.Errorf("sparse set.Elems(); got: %v; want: %v", got, sparse)
+	}
+
+	set = NewRoaring(dense...)
+	if got := kind(set); got != "run" {
+		t.Errorf("dense container; got: %v; want: run", got)
+	}
+	if got, want := set.Len(), len(dense); got != want {
+		t.Errorf("dense set.Len(); got: %v; want: %v", got, want)
+	}
+	set.Remove(100)
+	if got := kind(set); got != "array" {
+		t.Errorf("dense container after remove; got: %v; want: array", got)
+	}
+
+	// Operations between containers are optimized.
+	odd := NewRoaring[uint32]()
+	for _, e := range sparse {
+		odd.Insert(e + 1)
+	}
+	union := NewRoaring(sparse...).Union(odd).(Sorted[uint32])
+	if got := kind(union); got != "run" {
+		t.Errorf("union container; got: %v; want: run", got)
+	}
+	if got, want := union.Len(), 2*arrayMaxCard; got != want {
+		t.Errorf("union.Len(); got: %v; want: %v", got, want)
+	}
+	if got := union.Intersection(odd).Elems(); !slices.Equal(got, odd.Elems()) {
+		t.Errorf("union.Intersection(odd).Elems(); got: %v; want: %v", got, odd.Elems())
+	}
+	if got := union.Difference(odd).Elems(); !slices.Equal(got, sparse) {
+		t.Errorf("union.Difference(odd).Elems(); got: %v; want: %v", got, sparse)
+	}
+	if got := union.SymmetricDifference(odd).Elems(); !slices.Equal(got, sparse) {
+		t.Errorf("union.SymmetricDifference(odd).Elems(); got: %v; want: %v", got, sparse)
+	}
+	if union.Difference(union).Len() != 0 {
+		t.Errorf("union.Difference(union).Len(); got: %v; want: 0", union.Difference(union).Len())
+	}
+}
+
+func TestRoaringUint64(t *testing.T) {
+	set := NewRoaring[uint64](0, 1<<40, 1<<40+1, math.MaxUint64)
+	if got, want := set.Elems(), []uint64{0, 1 << 40, 1<<40 + 1, math.MaxUint64}; !slices.Equal(got, want) {
+		t.Errorf("set.Elems(); got: %v; want: %v", got, want)
+	}
+	if e, ok := set.Higher(math.MaxUint64); ok {
+		t.Errorf("set.Higher(MaxUint64); got: %v, true; want: false", e)
+	}
+	if e, ok := set.Lower(0); ok {
+		t.Errorf("set.Lower(0); got: %v, true; want: false", e)
+	}
+	if e, ok := set.Floor(1<<40 - 1); e != 0 || !ok {
+		t.Errorf("set.Floor(1<<40 - 1); got: %v, %v; want: 0, true", e, ok)
+	}
+	if e, ok := set.Ceiling(1<<40 + 2); e != math.MaxUint64 || !ok {
+		t.Errorf("set.Ceiling(1<<40 + 2); got: %v, %v; want: MaxUint64, true", e, ok)
+	}
+	if got := set.CountRange(1, math.MaxUint64); got != 2 {
+		t.Errorf("set.CountRange(1, MaxUint64); got: %v; want: 2", got)
+	}
+	if got := set.SubSet(0, math.MaxUint64, Closed).Len(); got != 4 {
+		t.Errorf("set.SubSet(0, MaxUint64, Closed).Len(); got: %v; want: 4", got)
+	}
+}
+
+func TestRoaringRandom(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("random seed: %v", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	// Draw elements from a few containers, some of which are dense.
+	randElem := func() uint32 {
+		key := uint32(rnd.Intn(4))
+		switch key {
+		case 0:
+			return key<<16 | uint32(rnd.Intn(1<<16))
+		case 1:
+			return key<<16 | uint32(rnd.Intn(8000))
+		default:
+			return key<<16 | uint32(rnd.Intn(100)*100)
+		}
+	}
+	randElems := func(n int) []uint32 {
+		elems := make([]uint32, n)
+		for i := range elems {
+			elems[i] = randElem()
+		}
+		return elems
+	}
+
+	set, want := NewRoaring[uint32](), NewSorted[uint32]()
+	for i := range 200 {
+		elems := randElems(rnd.Intn(2000))
+		other := NewRoaring(elems...)
+		switch i % 4 {
+		case 0:
+			set.InsertSet(other)
+			want.InsertAll(elems...)
+		case 1:
+			set.RemoveSet(other)
+			want.RemoveAll(elems...)
+		case 2:
+			set = set.SymmetricDifference(other).(Sorted[uint32])
+			want = want.SymmetricDifference(NewSorted(elems...)).(Sorted[uint32])
+		case 3:
+			e := randElem()
+			set.Insert(e)
+			want.Insert(e)
+			e = randElem()
+			set.Remove(e)
+			want.Remove(e)
+		}
+		if !slices.Equal(set.Elems(), want.Elems()) {
+			t.Fatalf("step %d: unexpected elements", i)
+		}
+		if got, want := set.Len(), want.Len(); got != want {
+			t.Fatalf("step %d: set.Len(); got: %v; want: %v", i, got, want)
+		}
+		if n := want.Len(); n > 0 {
+			k := rnd.Intn(n)
+			if got, want := set.At(k), want.At(k); got != want {
+				t.Fatalf("step %d: set.At(%d); got: %v; want: %v", i, k, got, want)
+			}
+		}
+		e := randElem()
+		if got, want := set.Rank(e), want.Rank(e); got != want {
+			t.Fatalf("step %d: set.Rank(%v); got: %v; want: %v", i, e, got, want)
+		}
+		lo, hi := randElem(), randElem()
+		if got, want := set.CountRange(lo, hi), want.CountRange(lo, hi); got != want {
+			t.Fatalf("step %d: set.CountRange(%v, %v); got: %v; want: %v", i, lo, hi, got, want)
+		}
+		if got, want := slices.Collect(set.Backward()), slices.Collect(want.Backward()); !slices.Equal(got, want) {
+			t.Fatalf("step %d: unexpected backward elements", i)
+		}
+	}
+}
@@ -9,6 +9,8 @@
 package sets
 
 import (
+	"iter"
+
 	"golang.org/x/exp/maps"
 )
 
@@ -66,11 +68,23 @@ type Set[E any] interface {
 	// Range calls the given function with each element of the set until
 	// there are no elements remaining or the function returns false.
 	Range(fn func(elem E) bool)
+	// All returns an iterator over the elements of the set.
+	All() iter.Seq[E]
 
 	// Clone returns a copy of the set.
 	Clone() Set[E]
 }
 
+// A Membership reports whether elements are members of a collection.
+// It's implemented by every Set and by approximate membership filters,
+// which may report false positives, but never false negatives.
+type Membership[E any] interface {
+	// Contains returns a value indicating if the given element is a member.
+	Contains(elem E) bool
+	// ContainsAll returns a value indicating if all the given elements are members.
+	ContainsAll(elems ...E) bool
+}
+
 // New returns a set initialized with the given elements.
 func New[E comparable](elems ...E) Set[E] {
 	set := make(table[E], len(elems))
@@ -80,6 +94,79 @@ func New[E comparable](elems ...E) Set[E] {
 	return set
 }
 
+// Collect returns a set initialized with the elements of the given sequence.
+func Collect[E comparable](seq iter.Seq[E]) Set[E] {
+	set := make(table[E])
+	for elem := range seq {
+		set[elem] = struct{}{}
+	}
+	return set
+}
+
+// InsertSeq adds the elements of the given sequence to the set which are not in the set.
+// It's semantically equivalent to calling Insert with each of the elements,
+// but may be more efficient.
+func InsertSeq[E any](set Set[E], seq iter.Seq[E]) {
+	if s, ok := set.(seqInserter[E]); ok {
+		s.insertSeq(seq)
+		return
+	}
+	for elem := range seq {
+		set.Insert(elem)
+	}
+}
+
+type seqInserter[E any] interface {
+	insertSeq(seq iter.Seq[E])
+}
+
+// Equal returns a value indicating if a and b contain the same elements.
+func Equal[E any](a, b Set[E]) bool {
+	return a.Len() == b.Len() && a.ContainsSet(b)
+}
+
+// IsSubset returns a value indicating if all the elements of a are in b (A ⊆ B).
+func IsSubset[E any](a, b Set[E]) bool {
+	return a.Len() <= b.Len() && b.ContainsSet(a)
+}
+
+// IsProperSubset returns a value indicating if all the elements of a are in b
+// and b has elements that aren't in a (A ⊂ B).
+func IsProperSubset[E any](a, b Set[E]) bool {
+	return a.Len() < b.Len() && b.ContainsSet(a)
+}
+
+// IsSuperset returns a value indicating if all the elements of b are in a (A ⊇ B).
+func IsSuperset[E any](a, b Set[E]) bool {
+	return IsSubset(b, a)
+}
+
+// IsDisjoint returns a value indicating if a and b have no elements in common (A ∩ B = ∅).
+// It's semantically equivalent to checking if a.Intersection(b) is empty,
+// but may be more efficient.
+func IsDisjoint[E any](a, b Set[E]) bool {
+	if s, ok := a.(disjointer[E]); ok {
+		if disjoint, ok := s.isDisjoint(b); ok {
+			return disjoint
+		}
+	}
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	disjoint := true
+	a.Range(func(e E) bool {
+		disjoint = !b.Contains(e)
+		return disjoint
+	})
+	return disjoint
+}
+
+type disjointer[E any] interface {
+	// isDisjoint returns a value indicating if the set and other have no
+	// elements in common and a value indicating if it could be determined.
+	isDisjoint(other Set[E]) (disjoint, ok bool)
+}
+
 type table[E comparable] map[E]struct{}
 
 func (set table[E]) Contains(elem E) bool {
@@ -150,6 +237,12 @@ func (set table[E]) InsertSet(other Set[E]) {
 	}
 }
 
+func (set table[E]) insertSeq(seq iter.Seq[E]) {
+	for e := range seq {
+		set[e] = struct{}{}
+	}
+}
+
 func (set table[E]) Remove(elem E) {
 	delete(set, elem)
 }
@@ -277,6 +370,16 @@ func (set table[E]) Range(fn func(v E) bool) {
 	}
 }
 
+func (set table[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for v := range set {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 func (set table[E]) Clone() Set[E] {
 	return maps.Clone(set)
 }
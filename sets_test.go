@@ -8,6 +8,7 @@ package sets
 
 import (
 	"cmp"
+	"hash/maphash"
 	"math/rand"
 	"reflect"
 	"slices"
@@ -21,6 +22,11 @@ func cmpPtrVal[T cmp.Ordered](a, b *T) int {
 	return cmp.Compare(*a, *b)
 }
 
+// hashPtrVal hashes the value of the pointer, so pointers to equal values collide.
+func hashPtrVal[T comparable](seed maphash.Seed, p *T) uint64 {
+	return hashComparable(seed, *p)
+}
+
 func toRunePtrs(s string) []*rune {
 	runes := []rune(s)
 	ptrs := make([]*rune, len(s))
@@ -60,6 +66,70 @@ func TestOrderedSets(t *testing.T) {
 			sorted:  true,
 			uniqCmp: true,
 		},
+		{
+			name:    "btree",
+			newSet:  func(elems ...rune) Set[rune] { return NewSortedBTree(elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  true,
+			uniqCmp: true,
+		},
+		{
+			name:    "concurrent",
+			newSet:  func(elems ...rune) Set[rune] { return NewConcurrent(elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "sharded",
+			newSet:  func(elems ...rune) Set[rune] { return NewSharded(4, elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "linked",
+			newSet:  func(elems ...rune) Set[rune] { return NewOrdered(elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "keyed",
+			newSet:  func(elems ...rune) Set[rune] { return NewKeyFunc(func(r rune) string { return string(r) }, elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "hashed",
+			newSet:  func(elems ...rune) Set[rune] { return NewHashFunc(hashComparable[rune], equal[rune], elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "persistent",
+			newSet:  func(elems ...rune) Set[rune] { return NewPersistent(elems...).AsSet() },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "bitset",
+			newSet:  func(elems ...rune) Set[rune] { return NewBitSet(elems...) },
+			cmpFn:   cmp.Compare[rune],
+			eqFn:    equal[rune],
+			sorted:  true,
+			uniqCmp: true,
+		},
 		{
 			name:   "external",
 			newSet: func(elems ...rune) Set[rune] { return &externalSet[rune]{New(elems...)} },
@@ -86,6 +156,64 @@ func TestUnorderedSets(t *testing.T) {
 			sorted:  true,
 			uniqCmp: false,
 		},
+		{
+			name: "btree",
+			newSet: func(elems ...*rune) Set[*rune] {
+				return NewSortedBTreeCmpEqFunc(cmpPtrVal[rune], equal[*rune], elems...)
+			},
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  true,
+			uniqCmp: false,
+		},
+		{
+			name:    "concurrent",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewConcurrent(elems...) },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "sharded",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewSharded(4, elems...) },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "linked",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewOrdered(elems...) },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "keyed",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewKeyFunc(func(p *rune) *rune { return p }, elems...) },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "hashed",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewHashFunc(hashPtrVal[rune], equal[*rune], elems...) },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
+		{
+			name:    "persistent",
+			newSet:  func(elems ...*rune) Set[*rune] { return NewPersistent(elems...).AsSet() },
+			cmpFn:   cmpPtrVal[rune],
+			eqFn:    equal[*rune],
+			sorted:  false,
+			uniqCmp: true,
+		},
 		{
 			name:   "external",
 			newSet: func(elems ...*rune) Set[*rune] { return &externalSet[*rune]{New(elems...)} },
@@ -94,6 +222,43 @@ func TestUnorderedSets(t *testing.T) {
 	}).test(t)
 }
 
+func TestCollect(t *testing.T) {
+	elems := []rune("abcdefghijklmnop")
+	want := NewSorted(elems...)
+	if set := Collect(slices.Values(elems)); set.Len() != want.Len() || !want.ContainsSet(set) {
+		t.Fatalf("Collect(...); got: %v; want: %v", set.Elems(), want.Elems())
+	}
+	if set := CollectSorted(slices.Values(elems)); !slices.Equal(set.Elems(), want.Elems()) {
+		t.Fatalf("CollectSorted(...); got: %v; want: %v", set.Elems(), want.Elems())
+	}
+}
+
+func TestInsertSeq(t *testing.T) {
+	elems := []rune("abcdefghijklmnop")
+	want := NewSorted(elems...)
+	for _, tt := range []struct {
+		name   string
+		newSet func(elems ...rune) Set[rune]
+	}{
+		{"table", New[rune]},
+		{"ordered", func(elems ...rune) Set[rune] { return NewSorted(elems...) }},
+		{"sorted", func(elems ...rune) Set[rune] { return NewSortedCmpFunc(cmp.Compare[rune], elems...) }},
+		{"linked", func(elems ...rune) Set[rune] { return NewOrdered(elems...) }},
+		{"external", func(elems ...rune) Set[rune] { return &externalSet[rune]{New(elems...)} }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := tt.newSet(elems[:4]...)
+			InsertSeq(set, slices.Values([]rune("pnolmjkhigefcdbadbp")))
+			if set.Len() != want.Len() || !want.ContainsSet(set) {
+				t.Fatalf("InsertSeq(...); got: %v; want: %v", set.Elems(), want.Elems())
+			}
+			if _, ok := set.(Sorted[rune]); ok && !slices.Equal(set.Elems(), want.Elems()) {
+				t.Fatalf("InsertSeq(...) order; got: %q; want: %q", set.Elems(), want.Elems())
+			}
+		})
+	}
+}
+
 type setType[E any] struct {
 	name    string
 	newSet  func(elems ...E) Set[E]
@@ -146,6 +311,7 @@ func (st *setTester[E]) test(t *testing.T) {
 			t.Run("Contains", func(t *testing.T) { st.testContains(t, typ) })
 			t.Run("ContainsAll", func(t *testing.T) { st.testContainsAll(t, typ) })
 			t.Run("ContainsSet", func(t *testing.T) { st.testContainsSet(t, typ) })
+			t.Run("Relations", func(t *testing.T) { st.testRelations(t, typ) })
 			t.Run("Insert", func(t *testing.T) { st.testInsert(t, typ) })
 			t.Run("InsertAll", func(t *testing.T) { st.testInsertAll(t, typ) })
 			t.Run("InsertSet", func(t *testing.T) { st.testInsertSet(t, typ) })
@@ -157,6 +323,8 @@ func (st *setTester[E]) test(t *testing.T) {
 			t.Run("Difference", func(t *testing.T) { st.testDifference(t, typ) })
 			t.Run("SymmetricDifference", func(t *testing.T) { st.testSymmetricDifference(t, typ) })
 			t.Run("Range", func(t *testing.T) { st.testRange(t, typ) })
+			t.Run("All", func(t *testing.T) { st.testAll(t, typ) })
+			t.Run("Backward", func(t *testing.T) { st.testBackward(t, typ) })
 			t.Run("Elems", func(t *testing.T) { st.testElems(t, typ) })
 			t.Run("Clone", func(t *testing.T) { st.testClone(t, typ) })
 		})
@@ -222,6 +390,45 @@ func (st *setTester[E]) testContainsSet(t *testing.T, typ *setType[E]) {
 	}
 }
 
+func (st *setTester[E]) testRelations(t *testing.T, typ *setType[E]) {
+	set := typ.newSet(st.elems[:st.half]...)
+	for _, otherTyp := range st.setTypes {
+		t.Run(otherTyp.name, func(t *testing.T) {
+			for _, tt := range []struct {
+				name                                      string
+				other                                     Set[E]
+				equal, subset, proper, superset, disjoint bool
+			}{
+				{"empty", otherTyp.newSet(), false, false, false, true, true},
+				{"same", otherTyp.newSet(st.elems[:st.half]...), true, true, false, true, false},
+				{"sub", otherTyp.newSet(st.elems[:st.quarter]...), false, false, false, true, false},
+				{"super", otherTyp.newSet(st.elems...), false, true, true, false, false},
+				{"overlap", otherTyp.newSet(st.elems[st.quarter : st.half+st.quarter]...), false, false, false, false, false},
+				{"disjoint", otherTyp.newSet(st.elems[st.half:]...), false, false, false, false, true},
+			} {
+				if got := Equal(set, tt.other); got != tt.equal {
+					t.Errorf("Equal(set, %s); got: %v; want: %v", tt.name, got, tt.equal)
+				}
+				if got := IsSubset(set, tt.other); got != tt.subset {
+					t.Errorf("IsSubset(set, %s); got: %v; want: %v", tt.name, got, tt.subset)
+				}
+				if got := IsProperSubset(set, tt.other); got != tt.proper {
+					t.Errorf("IsProperSubset(set, %s); got: %v; want: %v", tt.name, got, tt.proper)
+				}
+				if got := IsSuperset(set, tt.other); got != tt.superset {
+					t.Errorf("IsSuperset(set, %s); got: %v; want: %v", tt.name, got, tt.superset)
+				}
+				if got := IsDisjoint(set, tt.other); got != tt.disjoint {
+					t.Errorf("IsDisjoint(set, %s); got: %v; want: %v", tt.name, got, tt.disjoint)
+				}
+				if got := IsDisjoint(tt.other, set); got != tt.disjoint {
+					t.Errorf("IsDisjoint(%s, set); got: %v; want: %v", tt.name, got, tt.disjoint)
+				}
+			}
+		})
+	}
+}
+
 func (st *setTester[E]) testInsert(t *testing.T, typ *setType[E]) {
 	set := typ.newSet()
 	// Insert each element one at a time.
@@ -411,6 +618,54 @@ func (st *setTester[E]) testRange(t *testing.T, typ *setType[E]) {
 	}
 }
 
+func (st *setTester[E]) testAll(t *testing.T, typ *setType[E]) {
+	// Make sure we iterate over all values (in order if sorted set).
+	set := typ.newSet(st.elems...)
+	var got []E
+	for e := range set.All() {
+		got = append(got, e)
+	}
+	if !typ.sorted {
+		got = typ.sort(got)
+	}
+	want := typ.sort(slices.Clone(st.elems))
+	if diff := compare.Diff(got, want); diff != "" {
+		t.Fatal("Unexpected diff in set.All():\n", diff)
+	}
+	// Make sure break works.
+	i := 0
+	for range set.All() {
+		if i++; i == st.half {
+			break
+		}
+	}
+	if got, want := i, st.half; got != want {
+		t.Fatalf("All not stopped after half elements; got: %v; want: %v", got, want)
+	}
+}
+
+func (st *setTester[E]) testBackward(t *testing.T, typ *setType[E]) {
+	if !typ.sorted {
+		t.Skip("not sorted")
+	}
+	set := typ.newSet(st.elems...).(Sorted[E])
+	got := slices.Collect(set.Backward())
+	slices.Reverse(got)
+	if diff := compare.Diff(got, set.Elems()); diff != "" {
+		t.Fatal("Unexpected diff in set.Backward():\n", diff)
+	}
+	// Make sure break works.
+	i := 0
+	for range set.Backward() {
+		if i++; i == st.half {
+			break
+		}
+	}
+	if got, want := i, st.half; got != want {
+		t.Fatalf("Backward not stopped after half elements; got: %v; want: %v", got, want)
+	}
+}
+
 func (st *setTester[E]) testClone(t *testing.T, typ *setType[E]) {
 	set := typ.newSet(st.elems...)
 	clone := set.Clone()
@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"runtime"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// NewSharded returns a set that is safe for concurrent use by multiple goroutines,
+// initialized with the given elements. The elements are partitioned by hash into
+// the given number of shards, rounded up to a power of two, each of which has its
+// own lock. If shards is less than one, it uses one shard per logical CPU.
+//
+// Operations on a single element are linearizable.
+//
+// Len, Elems, Clone, ContainsAll, ContainsSet, Intersection, Union, Difference,
+// and SymmetricDifference are strongly consistent: they lock all the shards,
+// so they observe the set at a single point in time. The other set of a binary
+// operation is read without the locks held: ContainsSet reads it before the set
+// is locked, while Intersection, Union, Difference, and SymmetricDifference read
+// it after the set is copied and unlocked. So if the other set is also modified
+// concurrently, the operation isn't atomic with respect to both sets.
+//
+// InsertAll, InsertSet, RemoveAll, and RemoveSet are atomic within each shard,
+// but not across shards, so concurrent operations may observe some of the changes
+// without the others.
+//
+// Range and All are weakly consistent: they iterate over a snapshot of one shard
+// at a time, so they may or may not reflect concurrent changes to other shards,
+// but they never return an element more than once. The set may be modified
+// during iteration.
+func NewSharded[E comparable](shards int, elems ...E) Set[E] {
+	set := newSharded[E](shards)
+	for _, e := range elems {
+		set.shard(e).set[e] = struct{}{}
+	}
+	return set
+}
+
+func newSharded[E comparable](shards int) *sharded[E] {
+	if shards < 1 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	shards = 1 << bits.Len(uint(shards-1)) // Round up to a power of two.
+	set := &sharded[E]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]shard[E], shards),
+	}
+	for i := range set.shards {
+		set.shards[i].set = make(table[E])
+	}
+	return set
+}
+
+type sharded[E comparable] struct {
+	seed   maphash.Seed
+	shards []shard[E]
+}
+
+// cacheLineSize is the size of a cache line on common architectures.
+const cacheLineSize = 64
+
+type shard[E comparable] struct {
+	mu  sync.RWMutex
+	set table[E]
+	// Pad to a multiple of the cache line size to prevent false sharing.
+	_ [(cacheLineSize - (unsafe.Sizeof(sync.RWMutex{})+unsafe.Sizeof(table[E](nil)))%cacheLineSize) % cacheLineSize]byte
+}
+
+func (set *sharded[E]) Contains(elem E) bool {
+	s := set.shard(elem)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.set[elem]
+	return ok
+}
+
+func (set *sharded[E]) ContainsAll(elems ...E) bool {
+	set.rlockAll()
+	defer set.runlockAll()
+	for _, e := range elems {
+		if _, ok := set.shard(e).set[e]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (set *sharded[E]) ContainsSet(other Set[E]) bool {
+	return set.ContainsAll(other.Elems()...)
+}
+
+func (set *sharded[E]) Insert(elem E) {
+	s := set.shard(elem)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set[elem] = struct{}{}
+}
+
+func (set *sharded[E]) InsertAll(elems ...E) {
+	for i, elems := range set.partition(elems) {
+		if len(elems) == 0 {
+			continue
+		}
+		s := &set.shards[i]
+		s.mu.Lock()
+		s.set.InsertAll(elems...)
+		s.mu.Unlock()
+	}
+}
+
+func (set *sharded[E]) InsertSet(other Set[E]) {
+	set.InsertAll(other.Elems()...)
+}
+
+func (set *sharded[E]) insertSeq(seq iter.Seq[E]) {
+	set.InsertAll(slices.Collect(seq)...)
+}
+
+func (set *sharded[E]) Remove(elem E) {
+	s := set.shard(elem)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.set, elem)
+}
+
+func (set *sharded[E]) RemoveAll(elems ...E) {
+	for i, elems := range set.partition(elems) {
+		if len(elems) == 0 {
+			continue
+		}
+		s := &set.shards[i]
+		s.mu.Lock()
+		s.set.RemoveAll(elems...)
+		s.mu.Unlock()
+	}
+}
+
+func (set *sharded[E]) RemoveSet(other Set[E]) {
+	set.RemoveAll(other.Elems()...)
+}
+
+func (set *sharded[E]) Intersection(other Set[E]) Set[E] {
+	return set.from(set.snapshot().Intersection(other))
+}
+
+func (set *sharded[E]) Union(other Set[E]) Set[E] {
+	return set.from(set.snapshot().Union(other))
+}
+
+func (set *sharded[E]) Difference(other Set[E]) Set[E] {
+	return set.from(set.snapshot().Difference(other))
+}
+
+func (set *sharded[E]) SymmetricDifference(other Set[E]) Set[E] {
+	return set.from(set.snapshot().SymmetricDifference(other))
+}
+
+func (set *sharded[E]) Len() int {
+	set.rlockAll()
+	defer set.runlockAll()
+	n := 0
+	for i := range set.shards {
+		n += len(set.shards[i].set)
+	}
+	return n
+}
+
+func (set *sharded[E]) Elems() []E {
+	set.rlockAll()
+	defer set.runlockAll()
+	n := 0
+	for i := range set.shards {
+		n += len(set.shards[i].set)
+	}
+	elems := make([]E, 0, n)
+	for i := range set.shards {
+		for e := range set.shards[i].set {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+func (set *sharded[E]) Range(fn func(v E) bool) {
+	for i := range set.shards {
+		s := &set.shards[i]
+		s.mu.RLock()
+		elems := s.set.Elems()
+		s.mu.RUnlock()
+		for _, v := range elems {
+			if !fn(v) {
+				return
+			}
+		}
+	}
+}
+
+func (set *sharded[E]) All() iter.Seq[E] {
+	return set.Range
+}
+
+func (set *sharded[E]) Clone() Set[E] {
+	return set.from(set.snapshot())
+}
+
+// shard returns the shard to which the element belongs.
+func (set *sharded[E]) shard(elem E) *shard[E] {
+	return &set.shards[set.index(elem)]
+}
+
+func (set *sharded[E]) index(elem E) int {
+	return int(hashComparable(set.seed, elem) & uint64(len(set.shards)-1))
+}
+
+// partition returns the elements grouped by shard index.
+func (set *sharded[E]) partition(elems []E) [][]E {
+	parts := make([][]E, len(set.shards))
+	for _, e := range elems {
+		i := set.index(e)
+		parts[i] = append(parts[i], e)
+	}
+	return parts
+}
+
+// snapshot returns a copy of the elements at a single point in time.
+func (set *sharded[E]) snapshot() table[E] {
+	set.rlockAll()
+	defer set.runlockAll()
+	s := make(table[E])
+	for i := range set.shards {
+		for e := range set.shards[i].set {
+			s[e] = struct{}{}
+		}
+	}
+	return s
+}
+
+// from returns a new set with the same number of shards and the elements of other.
+func (set *sharded[E]) from(other Set[E]) *sharded[E] {
+	s := newSharded[E](len(set.shards))
+	other.Range(func(e E) bool {
+		s.shard(e).set[e] = struct{}{}
+		return true
+	})
+	return s
+}
+
+// rlockAll read locks all the shards in order.
+func (set *sharded[E]) rlockAll() {
+	for i := range set.shards {
+		set.shards[i].mu.RLock()
+	}
+}
+
+func (set *sharded[E]) runlockAll() {
+	for i := range set.shards {
+		set.shards[i].mu.RUnlock()
+	}
+}
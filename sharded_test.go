@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestShardedShards(t *testing.T) {
+	for _, tt := range []struct {
+		shards int
+		want   int
+	}{
+		{1, 1},
+		{2, 2},
+		{3, 4},
+		{64, 64},
+		{65, 128},
+	} {
+		if got := len(NewSharded[int](tt.shards).(*sharded[int]).shards); got != tt.want {
+			t.Errorf("NewSharded(%v) shards; got: %v; want: %v", tt.shards, got, tt.want)
+		}
+	}
+	if got, min := len(NewSharded[int](0).(*sharded[int]).shards), runtime.GOMAXPROCS(0); got < min {
+		t.Errorf("NewSharded(0) shards; got: %v; want at least: %v", got, min)
+	}
+}
+
+func TestShardPadding(t *testing.T) {
+	if size := unsafe.Sizeof(shard[string]{}); size%cacheLineSize != 0 {
+		t.Errorf("shard size; got: %v; want a multiple of %v", size, cacheLineSize)
+	}
+}
+
+func TestShardedConcurrent(t *testing.T) {
+	a := NewSharded[int](8)
+	b := NewSharded[int](8)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x, y := a, b
+			if i%2 == 1 {
+				x, y = b, a
+			}
+			for k := range 200 {
+				x.Insert(i*1000 + k)
+				x.InsertAll(i*1000+k, -(i*1000 + k + 1))
+				x.Remove(-(i*1000 + k + 1))
+				x.InsertSet(y)
+				x.ContainsSet(y)
+				x.SymmetricDifference(y)
+				for range x.All() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	u := a.Union(b)
+	for i := range 8 {
+		for k := range 200 {
+			if !u.Contains(i*1000 + k) {
+				t.Fatalf("a.Union(b).Contains(%v); got: false; want: true", i*1000+k)
+			}
+		}
+	}
+	a.RemoveSet(u)
+	b.RemoveSet(b)
+	if got := a.Len() + b.Len(); got != 0 {
+		t.Fatalf("a.Len() + b.Len(); got: %v; want: 0", got)
+	}
+}
@@ -8,18 +8,89 @@ package sets
 
 import (
 	"cmp"
+	"iter"
 	"slices"
 	"sort"
 )
 
 // Sorted is a set whose elements are sorted.
-// Elems and Range will return the elements in sorted order.
+// Elems, Range, and All will return the elements in sorted order.
 type Sorted[E any] interface {
 	Set[E]
 
+	// Backward returns an iterator over the elements of the set in reverse sorted order.
+	Backward() iter.Seq[E]
+
+	// Min returns the least element in the set.
+	// It returns false if the set is empty.
+	Min() (E, bool)
+	// Max returns the greatest element in the set.
+	// It returns false if the set is empty.
+	Max() (E, bool)
+	// Floor returns the greatest element in the set less than or equal to elem.
+	// If the set contains elem, it returns the element identical to elem.
+	// It returns false if there is no such element.
+	Floor(elem E) (E, bool)
+	// Ceiling returns the least element in the set greater than or equal to elem.
+	// If the set contains elem, it returns the element identical to elem.
+	// It returns false if there is no such element.
+	Ceiling(elem E) (E, bool)
+	// Lower returns the greatest element in the set strictly less than elem.
+	// It returns false if there is no such element.
+	Lower(elem E) (E, bool)
+	// Higher returns the least element in the set strictly greater than elem.
+	// It returns false if there is no such element.
+	Higher(elem E) (E, bool)
+
+	// SubSet returns a new set with the elements of the set between lo and hi.
+	// The bounds indicate whether lo and hi are included in the range.
+	// Only the elements in the range are copied.
+	SubSet(lo, hi E, bounds Bounds) Sorted[E]
+	// HeadSet returns a new set with the elements of the set strictly less than hi.
+	// Only the elements in the range are copied.
+	HeadSet(hi E) Sorted[E]
+	// TailSet returns a new set with the elements of the set greater than or equal to lo.
+	// Only the elements in the range are copied.
+	TailSet(lo E) Sorted[E]
+	// CountRange returns the number of elements in the set greater than or equal to lo
+	// and strictly less than hi.
+	CountRange(lo, hi E) int
+
+	// Rank returns the index of elem in sorted order if it's in the set.
+	// Otherwise, it returns the index at which elem would be inserted,
+	// which is after every element for which cmp(a, elem) <= 0. For sets
+	// that may contain unique elements for which cmp(a, elem) == 0,
+	// like those returned by NewSortedCmpEqFunc, that includes elements
+	// that aren't ordered before elem.
+	Rank(elem E) int
+	// At returns the element at index i in sorted order.
+	// It panics if i is out of range.
+	At(i int) E
+	// Slice returns a new set with the elements at indices [i, j) in sorted order.
+	// Only the elements in the range are copied.
+	// It panics if i or j is out of range or if i > j.
+	Slice(i, j int) Sorted[E]
+
 	search(E) (int, bool)
 }
 
+// Bounds indicates which endpoints are included in a range.
+// The endpoints of a range are compared with the elements of a sorted set
+// using its ordering, so every element that sorts equal to an included endpoint
+// is in the range and every element that sorts equal to an excluded endpoint isn't.
+type Bounds uint8
+
+const (
+	// Open excludes both endpoints of the range (lo, hi).
+	Open Bounds = 0
+	// IncludeLo includes the lower endpoint of the range [lo, hi).
+	IncludeLo Bounds = 1 << 0
+	// IncludeHi includes the upper endpoint of the range (lo, hi].
+	IncludeHi Bounds = 1 << 1
+	// Closed includes both endpoints of the range [lo, hi].
+	Closed = IncludeLo | IncludeHi
+)
+
 // A CmpFunc is a comparison function.
 // It returns 1 if a is greater than b.
 // It returns -1 if a is less than b.
@@ -48,6 +119,10 @@ func NewSortedCmpFunc[E any](cmp CmpFunc[E], elems ...E) Sorted[E] {
 // function is used to identify elements.
 //
 // It may contain unique elements for which cmp(a, b) == 0 and eq(a, b) == false.
+// Such elements are kept in the order they were inserted. When navigating the set,
+// Floor and Ceiling prefer an element identical to the given element; otherwise,
+// Floor returns the last and Ceiling returns the first of the elements for which
+// cmp(a, elem) == 0. Lower and Higher skip all elements for which cmp(a, elem) == 0.
 func NewSortedCmpEqFunc[E any](cmp CmpFunc[E], eq EqFunc[E], elems ...E) Sorted[E] {
 	return &sorted[E]{
 		elems: stableSortUniqCmpEq(slices.Clone(elems), cmp, eq),
@@ -56,6 +131,13 @@ func NewSortedCmpEqFunc[E any](cmp CmpFunc[E], eq EqFunc[E], elems ...E) Sorted[
 	}
 }
 
+// CollectSorted returns a sorted set initialized with the elements of the given sequence.
+func CollectSorted[E cmp.Ordered](seq iter.Seq[E]) Sorted[E] {
+	return &ordered[E]{
+		elems: stableSortUniqCmpEq(slices.Collect(seq), cmp.Compare[E], equal[E]),
+	}
+}
+
 type ordered[E cmp.Ordered] struct {
 	elems []E
 }
@@ -75,6 +157,19 @@ func (set *ordered[E]) ContainsAll(elems ...E) bool {
 }
 
 func (set *ordered[E]) ContainsSet(other Set[E]) bool {
+	if b, ok := set.mergeable(other); ok {
+		a := set.elems
+		for bv := range b {
+			for len(a) > 0 && a[0] < bv {
+				a = a[1:]
+			}
+			if len(a) == 0 || a[0] != bv {
+				return false
+			}
+			a = a[1:]
+		}
+		return true
+	}
 	switch other := other.(type) {
 	case table[E]:
 		for e := range other {
@@ -83,25 +178,6 @@ func (set *ordered[E]) ContainsSet(other Set[E]) bool {
 			}
 		}
 		return true
-	case *ordered[E]:
-		a := set.elems
-		b := other.elems
-		ai, an := 0, len(a)
-		bi, bn := 0, len(b)
-		for ai < an && bi < bn {
-			switch av, bv := a[ai], b[bi]; {
-			case av < bv:
-				ai++
-			case av == bv:
-				ai++
-				bi++
-			default: // ab > bv:
-				return false
-			}
-		}
-		return bi == bn
-	case *sorted[E]:
-		return set.ContainsAll(other.elems...)
 	default:
 		ok := true
 		other.Range(func(e E) bool {
@@ -112,6 +188,26 @@ func (set *ordered[E]) ContainsSet(other Set[E]) bool {
 	}
 }
 
+func (set *ordered[E]) isDisjoint(other Set[E]) (disjoint, ok bool) {
+	b, ok := set.mergeable(other)
+	if !ok {
+		return false, false
+	}
+	a := set.elems
+	for bv := range b {
+		for len(a) > 0 && a[0] < bv {
+			a = a[1:]
+		}
+		if len(a) == 0 {
+			break
+		}
+		if a[0] == bv {
+			return false, true
+		}
+	}
+	return true, true
+}
+
 func (set *ordered[E]) Insert(elem E) {
 	idx, found := set.search(elem)
 	if found {
@@ -134,6 +230,16 @@ func (set *ordered[E]) InsertSet(other Set[E]) {
 	set.insertAll(other.Elems()) // InsertAll without Clone.
 }
 
+func (set *ordered[E]) insertSeq(seq iter.Seq[E]) {
+	// Append the new elements to the set's slice instead of collecting them in another one.
+	// Merging them into the set's elements only overwrites them after reading them.
+	n := len(set.elems)
+	elems := slices.AppendSeq(set.elems, seq)
+	m := len(uniq(stableSort(elems[n:])))
+	set.elems = mergeUniqSortedLists(elems[:n], elems[n:n+m])
+	zero(elems[len(set.elems) : n+m])
+}
+
 func (set *ordered[E]) insertAll(elems []E) {
 	elems = uniq(stableSort(elems))
 	set.elems = mergeUniqSortedLists(set.elems, elems)
@@ -156,35 +262,32 @@ func (set *ordered[E]) RemoveAll(elems ...E) {
 }
 
 func (set *ordered[E]) RemoveSet(other Set[E]) {
-	switch other := other.(type) {
-	case *ordered[E]:
-		set.elems = diffUniqSortedLists(set.elems, other.elems)
-	default:
-		set.removeAll(other.Elems()) // RemoveAll without clone.
+	if b, ok := set.mergeable(other); ok {
+		set.elems = diffUniqSortedLists(set.elems, b)
+		return
 	}
+	set.removeAll(other.Elems()) // RemoveAll without clone.
 }
 
 func (set *ordered[E]) removeAll(elems []E) {
 	elems = stableSort(elems)
-	set.elems = diffUniqSortedLists(set.elems, elems)
+	set.elems = diffUniqSortedLists(set.elems, slices.Values(elems))
 }
 
 func (set *ordered[E]) Intersection(other Set[E]) Set[E] {
 	s := &ordered[E]{}
-	if other, ok := other.(*ordered[E]); ok {
-		a, b := set.elems, other.elems
-		ai, an := 0, len(a)
-		bi, bn := 0, len(b)
-		for ai < an && bi < bn {
-			switch av, bv := a[ai], b[bi]; {
-			case av < bv:
-				ai++
-			case av > bv:
-				bi++
-			default: // av == bv:
-				s.elems = append(s.elems, av)
-				ai++
-				bi++
+	if b, ok := set.mergeable(other); ok {
+		a := set.elems
+		for bv := range b {
+			for len(a) > 0 && a[0] < bv {
+				a = a[1:]
+			}
+			if len(a) == 0 {
+				break
+			}
+			if a[0] == bv {
+				s.elems = append(s.elems, a[0])
+				a = a[1:]
 			}
 		}
 		return s
@@ -198,27 +301,22 @@ func (set *ordered[E]) Intersection(other Set[E]) Set[E] {
 }
 
 func (set *ordered[E]) Union(other Set[E]) Set[E] {
-	if other, ok := other.(*ordered[E]); ok {
+	if b, ok := set.mergeable(other); ok {
 		s := &ordered[E]{}
-		a, b := set.elems, other.elems
-		ai, an := 0, len(a)
-		bi, bn := 0, len(b)
-		for ai < an && bi < bn {
-			switch av, bv := a[ai], b[bi]; {
-			case av < bv:
-				s.elems = append(s.elems, av)
-				ai++
-			case av > bv:
+		a := set.elems
+		for bv := range b {
+			for len(a) > 0 && a[0] < bv {
+				s.elems = append(s.elems, a[0])
+				a = a[1:]
+			}
+			if len(a) > 0 && a[0] == bv {
+				s.elems = append(s.elems, a[0])
+				a = a[1:]
+			} else {
 				s.elems = append(s.elems, bv)
-				bi++
-			default: // av == bv:
-				s.elems = append(s.elems, av)
-				ai++
-				bi++
 			}
 		}
-		s.elems = append(s.elems, a[ai:]...)
-		s.elems = append(s.elems, b[bi:]...)
+		s.elems = append(s.elems, a...)
 		return s
 	}
 	elems := stableSort(other.Elems())
@@ -228,23 +326,21 @@ func (set *ordered[E]) Union(other Set[E]) Set[E] {
 
 func (set *ordered[E]) Difference(other Set[E]) Set[E] {
 	s := &ordered[E]{}
-	if other, ok := other.(*ordered[E]); ok {
-		a, b := set.elems, other.elems
-		ai, an := 0, len(a)
-		bi, bn := 0, len(b)
-		for ai < an && bi < bn {
-			switch av, bv := a[ai], b[bi]; {
-			case av < bv:
-				s.elems = append(s.elems, av)
-				ai++
-			case av > bv:
-				bi++
-			default: // av == bv:
-				ai++
-				bi++
+	if b, ok := set.mergeable(other); ok {
+		a := set.elems
+		for bv := range b {
+			for len(a) > 0 && a[0] < bv {
+				s.elems = append(s.elems, a[0])
+				a = a[1:]
+			}
+			if len(a) == 0 {
+				break
+			}
+			if a[0] == bv {
+				a = a[1:]
 			}
 		}
-		s.elems = append(s.elems, a[ai:]...)
+		s.elems = append(s.elems, a...)
 		return s
 	}
 	for _, e := range set.elems {
@@ -257,25 +353,20 @@ func (set *ordered[E]) Difference(other Set[E]) Set[E] {
 
 func (set *ordered[E]) SymmetricDifference(other Set[E]) Set[E] {
 	s := &ordered[E]{}
-	if other, ok := other.(*ordered[E]); ok {
-		a, b := set.elems, other.elems
-		ai, an := 0, len(a)
-		bi, bn := 0, len(b)
-		for ai < an && bi < bn {
-			switch av, bv := a[ai], b[bi]; {
-			case av < bv:
-				s.elems = append(s.elems, av)
-				ai++
-			case av > bv:
+	if b, ok := set.mergeable(other); ok {
+		a := set.elems
+		for bv := range b {
+			for len(a) > 0 && a[0] < bv {
+				s.elems = append(s.elems, a[0])
+				a = a[1:]
+			}
+			if len(a) > 0 && a[0] == bv {
+				a = a[1:]
+			} else {
 				s.elems = append(s.elems, bv)
-				bi++
-			default: // av == bv:
-				ai++
-				bi++
 			}
 		}
-		s.elems = append(s.elems, a[ai:]...)
-		s.elems = append(s.elems, b[bi:]...)
+		s.elems = append(s.elems, a...)
 		return s
 	}
 	for _, e := range set.elems {
@@ -294,6 +385,16 @@ func (set *ordered[E]) SymmetricDifference(other Set[E]) Set[E] {
 	return s
 }
 
+// mergeable returns an iterator over the elements of the other set and true if they're
+// sorted and unique in natural order, like those of the set, so they can be merged with
+// the set's elements.
+func (set *ordered[E]) mergeable(other Set[E]) (iter.Seq[E], bool) {
+	if other, ok := other.(*ordered[E]); ok {
+		return slices.Values(other.elems), true
+	}
+	return sortedSeq(other, cmp.Compare[E])
+}
+
 func (set *ordered[E]) Len() int {
 	return len(set.elems)
 }
@@ -310,6 +411,80 @@ func (set *ordered[E]) Range(fn func(v E) bool) {
 	}
 }
 
+func (set *ordered[E]) All() iter.Seq[E] {
+	return slices.Values(set.elems)
+}
+
+func (set *ordered[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := len(set.elems) - 1; i >= 0; i-- {
+			if !yield(set.elems[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (set *ordered[E]) Min() (E, bool) {
+	return index(set.elems, 0)
+}
+
+func (set *ordered[E]) Max() (E, bool) {
+	return index(set.elems, len(set.elems)-1)
+}
+
+func (set *ordered[E]) Floor(elem E) (E, bool) {
+	idx, found := set.search(elem)
+	if found {
+		return set.elems[idx], true
+	}
+	return index(set.elems, idx-1)
+}
+
+func (set *ordered[E]) Ceiling(elem E) (E, bool) {
+	idx, _ := set.search(elem)
+	return index(set.elems, idx)
+}
+
+func (set *ordered[E]) Lower(elem E) (E, bool) {
+	return index(set.elems, set.lowerBound(elem)-1)
+}
+
+func (set *ordered[E]) Higher(elem E) (E, bool) {
+	return index(set.elems, set.upperBound(elem))
+}
+
+func (set *ordered[E]) SubSet(lo, hi E, bounds Bounds) Sorted[E] {
+	i, j := boundsIndex(set, lo, hi, bounds)
+	return &ordered[E]{elems: slices.Clone(set.elems[i:j])}
+}
+
+func (set *ordered[E]) HeadSet(hi E) Sorted[E] {
+	return &ordered[E]{elems: slices.Clone(set.elems[:set.lowerBound(hi)])}
+}
+
+func (set *ordered[E]) TailSet(lo E) Sorted[E] {
+	return &ordered[E]{elems: slices.Clone(set.elems[set.lowerBound(lo):])}
+}
+
+func (set *ordered[E]) CountRange(lo, hi E) int {
+	i, j := boundsIndex(set, lo, hi, IncludeLo)
+	return j - i
+}
+
+func (set *ordered[E]) Rank(elem E) int {
+	idx, _ := set.search(elem)
+	return idx
+}
+
+func (set *ordered[E]) At(i int) E {
+	return set.elems[i]
+}
+
+func (set *ordered[E]) Slice(i, j int) Sorted[E] {
+	return &ordered[E]{elems: slices.Clone(set.elems[i:j])}
+}
+
 func (set *ordered[E]) Clone() Set[E] {
 	return &ordered[E]{
 		elems: slices.Clone(set.elems),
@@ -322,6 +497,16 @@ func (set *ordered[E]) search(elem E) (idx int, found bool) {
 	return idx, (idx < n && elem == set.elems[idx])
 }
 
+// lowerBound returns the index of the first element greater than or equal to elem.
+func (set *ordered[E]) lowerBound(elem E) int {
+	return sort.Search(len(set.elems), func(i int) bool { return elem <= set.elems[i] })
+}
+
+// upperBound returns the index of the first element greater than elem.
+func (set *ordered[E]) upperBound(elem E) int {
+	return sort.Search(len(set.elems), func(i int) bool { return elem < set.elems[i] })
+}
+
 type sorted[E any] struct {
 	elems []E
 	cmp   func(E, E) int
@@ -378,6 +563,16 @@ func (set *sorted[E]) InsertSet(other Set[E]) {
 	set.insertAll(other.Elems()) // InsertAll without Clone.
 }
 
+func (set *sorted[E]) insertSeq(seq iter.Seq[E]) {
+	// Append the new elements to the set's slice instead of collecting them in another one.
+	// Merging them into the set's elements only overwrites them after reading them.
+	n := len(set.elems)
+	elems := slices.AppendSeq(set.elems, seq)
+	m := len(stableSortUniqCmpEq(elems[n:], set.cmp, set.eq))
+	set.elems = mergeSortedLists(elems[:n], elems[n:n+m], set.cmp, set.eq)
+	zero(elems[len(set.elems) : n+m])
+}
+
 func (set *sorted[E]) insertAll(elems []E) {
 	elems = stableSortUniqCmpEq(elems, set.cmp, set.eq)
 	set.elems = mergeSortedLists(set.elems, elems, set.cmp, set.eq)
@@ -477,6 +672,90 @@ func (set *sorted[E]) Range(fn func(v E) bool) {
 	}
 }
 
+func (set *sorted[E]) All() iter.Seq[E] {
+	return slices.Values(set.elems)
+}
+
+func (set *sorted[E]) Backward() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for i := len(set.elems) - 1; i >= 0; i-- {
+			if !yield(set.elems[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (set *sorted[E]) Min() (E, bool) {
+	return index(set.elems, 0)
+}
+
+func (set *sorted[E]) Max() (E, bool) {
+	return index(set.elems, len(set.elems)-1)
+}
+
+func (set *sorted[E]) Floor(elem E) (E, bool) {
+	idx, found := set.search(elem)
+	if found {
+		return set.elems[idx], true
+	}
+	return index(set.elems, idx-1) // Last element of the run.
+}
+
+func (set *sorted[E]) Ceiling(elem E) (E, bool) {
+	if idx, found := set.search(elem); found {
+		return set.elems[idx], true
+	}
+	return index(set.elems, set.lowerBound(elem)) // First element of the run.
+}
+
+func (set *sorted[E]) Lower(elem E) (E, bool) {
+	return index(set.elems, set.lowerBound(elem)-1)
+}
+
+func (set *sorted[E]) Higher(elem E) (E, bool) {
+	return index(set.elems, set.upperBound(elem))
+}
+
+func (set *sorted[E]) SubSet(lo, hi E, bounds Bounds) Sorted[E] {
+	i, j := boundsIndex(set, lo, hi, bounds)
+	return set.slice(i, j)
+}
+
+func (set *sorted[E]) HeadSet(hi E) Sorted[E] {
+	return set.slice(0, set.lowerBound(hi))
+}
+
+func (set *sorted[E]) TailSet(lo E) Sorted[E] {
+	return set.slice(set.lowerBound(lo), len(set.elems))
+}
+
+func (set *sorted[E]) CountRange(lo, hi E) int {
+	i, j := boundsIndex(set, lo, hi, IncludeLo)
+	return j - i
+}
+
+func (set *sorted[E]) Rank(elem E) int {
+	idx, _ := set.search(elem)
+	return idx
+}
+
+func (set *sorted[E]) At(i int) E {
+	return set.elems[i]
+}
+
+func (set *sorted[E]) Slice(i, j int) Sorted[E] {
+	return set.slice(i, j)
+}
+
+func (set *sorted[E]) slice(i, j int) *sorted[E] {
+	return &sorted[E]{
+		elems: slices.Clone(set.elems[i:j]),
+		cmp:   set.cmp,
+		eq:    set.eq,
+	}
+}
+
 func (set *sorted[E]) Clone() Set[E] {
 	return &sorted[E]{
 		elems: slices.Clone(set.elems),
@@ -502,6 +781,36 @@ func (set *sorted[E]) search(elem E) (idx int, found bool) {
 	return idx, false
 }
 
+// lowerBound returns the index of the first element for which cmp(elem, e) <= 0.
+func (set *sorted[E]) lowerBound(elem E) int {
+	return sort.Search(len(set.elems), func(i int) bool { return set.cmp(elem, set.elems[i]) <= 0 })
+}
+
+// upperBound returns the index of the first element for which cmp(elem, e) < 0.
+func (set *sorted[E]) upperBound(elem E) int {
+	return sort.Search(len(set.elems), func(i int) bool { return set.cmp(elem, set.elems[i]) < 0 })
+}
+
+type bounder[E any] interface {
+	lowerBound(elem E) int
+	upperBound(elem E) int
+}
+
+// boundsIndex returns the indices [i, j) of the elements in the range.
+func boundsIndex[E any](set bounder[E], lo, hi E, bounds Bounds) (i, j int) {
+	if bounds&IncludeLo != 0 {
+		i = set.lowerBound(lo)
+	} else {
+		i = set.upperBound(lo)
+	}
+	if bounds&IncludeHi != 0 {
+		j = set.upperBound(hi)
+	} else {
+		j = set.lowerBound(hi)
+	}
+	return i, max(i, j)
+}
+
 type insert[E any] struct {
 	i int
 	e E
@@ -599,21 +908,20 @@ func insertInto[E any](a, tail []E, inserts []insert[E]) []E {
 }
 
 // diffUniqSortedLists diffs B from A (e.g. A - B),
-// both of which must be sorted and contain unique values.
-func diffUniqSortedLists[E cmp.Ordered](a, b []E) []E {
+// both of which must be sorted and A must contain unique values.
+func diffUniqSortedLists[E cmp.Ordered](a []E, b iter.Seq[E]) []E {
 	var deletes []int
 	ai, an := 0, len(a)
-	bi, bn := 0, len(b)
-	for ai < an && bi < bn {
-		switch av, bv := a[ai], b[bi]; {
-		case av < bv:
+	for bv := range b {
+		for ai < an && a[ai] < bv {
 			ai++
-		case av > bv:
-			bi++
-		default: // av == bv:
+		}
+		if ai == an {
+			break
+		}
+		if a[ai] == bv {
 			deletes = append(deletes, ai)
 			ai++
-			bi++
 		}
 	}
 	return deleteFrom(a, deletes)
@@ -645,6 +953,87 @@ func diffSortedLists[E any](a, b []E, cmp CmpFunc[E], eq EqFunc[E]) []E {
 	return deleteFrom(a, deletes)
 }
 
+// diffSortedSeq diffs B from A (e.g. A - B), both of which must be sorted,
+// and B must be strictly increasing.
+func diffSortedSeq[E any](a []E, b iter.Seq[E], cmp CmpFunc[E], eq EqFunc[E]) []E {
+	var deletes []int
+	ai, an := 0, len(a)
+	for bv := range b {
+		for ai < an && cmp(a[ai], bv) < 0 {
+			ai++
+		}
+		if ai == an {
+			break
+		}
+		if cmp(a[ai], bv) == 0 {
+			ar := runEq(a[ai:], cmp)
+			for i, ae := range ar {
+				if eq(ae, bv) {
+					deletes = append(deletes, ai+i)
+				}
+			}
+			ai += len(ar)
+		}
+	}
+	return deleteFrom(a, deletes)
+}
+
+// intersectSortedSeq intersects A with B (e.g. A ∩ B), both of which must be sorted,
+// and B must be strictly increasing. It keeps the values of A.
+func intersectSortedSeq[E any](a []E, b iter.Seq[E], cmp CmpFunc[E], eq EqFunc[E]) []E {
+	n := 0
+	ai, an := 0, len(a)
+	for bv := range b {
+		for ai < an && cmp(a[ai], bv) < 0 {
+			ai++
+		}
+		if ai == an {
+			break
+		}
+		if cmp(a[ai], bv) == 0 {
+			ar := runEq(a[ai:], cmp)
+			for _, ae := range ar {
+				if eq(ae, bv) {
+					a[n] = ae // Slide kept elems left.
+					n++
+				}
+			}
+			ai += len(ar)
+		}
+	}
+	zero(a[n:])
+	return a[:n]
+}
+
+// symDiffSortedSeq returns the symmetric difference of A and B (e.g. A ∆ B),
+// both of which must be sorted, and B must be strictly increasing.
+func symDiffSortedSeq[E any](a []E, b iter.Seq[E], cmp CmpFunc[E], eq EqFunc[E]) []E {
+	var elems []E
+	ai, an := 0, len(a)
+	for bv := range b {
+		for ai < an && cmp(a[ai], bv) < 0 {
+			elems = append(elems, a[ai])
+			ai++
+		}
+		found := false
+		if ai < an && cmp(a[ai], bv) == 0 {
+			ar := runEq(a[ai:], cmp)
+			for _, ae := range ar {
+				if eq(ae, bv) {
+					found = true
+				} else {
+					elems = append(elems, ae)
+				}
+			}
+			ai += len(ar)
+		}
+		if !found {
+			elems = append(elems, bv) // Insert at the end of the run.
+		}
+	}
+	return append(elems, a[ai:]...)
+}
+
 func deleteFrom[E any](a []E, deletes []int) []E {
 	// [ a, b, c, d, e, f, g, h, i, j, k, l, m, n, o, p ]
 	//   - [ b-1, g-6, j-9, k-10, n-13, o-14, p-15 ]
@@ -715,17 +1104,42 @@ func stableSortUniqCmpEq[T any](list []T, cmp CmpFunc[T], eq EqFunc[T]) []T {
 }
 
 // stableSort stable sorts the list using O(n*log(n)) compares and O(n*log(n)*log(n)) swaps.
+// It only uses O(n) compares if the list is already sorted.
 func stableSort[E cmp.Ordered](list []E) []E {
-	slices.SortStableFunc(list, cmp.Compare[E])
+	if !slices.IsSorted(list) {
+		slices.SortStableFunc(list, cmp.Compare[E])
+	}
 	return list
 }
 
 // stableSortCmp stable sorts the list using O(n*log(n)) compares and O(n*log(n)*log(n)) swaps.
+// It only uses O(n) compares if the list is already sorted.
 func stableSortCmp[T any](list []T, cmp CmpFunc[T]) []T {
-	slices.SortStableFunc(list, cmp)
+	if !slices.IsSortedFunc(list, cmp) {
+		slices.SortStableFunc(list, cmp)
+	}
 	return list
 }
 
+// sortedSeq returns an iterator over the elements of the set and true if it's Sorted
+// and its elements are strictly increasing by cmp, so they can be merged with other
+// lists sorted by cmp. It walks the elements to check their order instead of copying
+// them. Otherwise, it returns nil and false.
+func sortedSeq[E any](set Set[E], cmp CmpFunc[E]) (iter.Seq[E], bool) {
+	if _, ok := set.(Sorted[E]); !ok {
+		return nil, false
+	}
+	var prev E
+	first := true
+	for e := range set.All() {
+		if !first && cmp(prev, e) >= 0 {
+			return nil, false
+		}
+		prev, first = e, false
+	}
+	return set.All(), true
+}
+
 // uniq removes item duplicates in place and preserves order using O(n) compares.
 func uniq[E cmp.Ordered](list []E) []E {
 	n := len(slices.Compact(list))
@@ -777,6 +1191,16 @@ func uniqEqSlow[T any](list []T, eq EqFunc[T]) []T {
 	return list[:dst]
 }
 
+// index returns the element at index i and true if i is in range.
+// Otherwise, it returns the zero value and false.
+func index[E any](elems []E, i int) (E, bool) {
+	if i < 0 || i >= len(elems) {
+		var zero E
+		return zero, false
+	}
+	return elems[i], true
+}
+
 func zero[T any](s []T) {
 	var empty T
 	for i := range s {
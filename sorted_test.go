@@ -8,6 +8,7 @@ package sets
 
 import (
 	"cmp"
+	"math/rand"
 	"slices"
 	"strings"
 	"testing"
@@ -153,3 +154,307 @@ func TestStableSortUniq(t *testing.T) {
 		})
 	}
 }
+
+type sortedType struct {
+	name   string
+	newSet func(elems ...uint32) Sorted[uint32]
+}
+
+var sortedTypes = []sortedType{
+	{
+		name:   "ordered",
+		newSet: NewSorted[uint32],
+	},
+	{
+		name:   "sorted",
+		newSet: func(elems ...uint32) Sorted[uint32] { return NewSortedCmpFunc(cmp.Compare[uint32], elems...) },
+	},
+	{
+		name:   "btree",
+		newSet: NewSortedBTree[uint32],
+	},
+	{
+		name:   "bitset",
+		newSet: NewBitSet[uint32],
+	},
+	{
+		name:   "roaring",
+		newSet: NewRoaring[uint32],
+	},
+}
+
+func TestSortedNavigation(t *testing.T) {
+	type result struct {
+		Elem uint32
+		OK   bool
+	}
+	for _, typ := range sortedTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			empty := typ.newSet()
+			if e, ok := empty.Min(); ok {
+				t.Errorf("empty.Min(); got: %v, true; want: false", e)
+			}
+			if e, ok := empty.Max(); ok {
+				t.Errorf("empty.Max(); got: %v, true; want: false", e)
+			}
+			if e, ok := empty.Floor(1); ok {
+				t.Errorf("empty.Floor(1); got: %v, true; want: false", e)
+			}
+
+			set := typ.newSet(40, 10, 30, 20)
+			if e, ok := set.Min(); e != 10 || !ok {
+				t.Errorf("set.Min(); got: %v, %v; want: 10, true", e, ok)
+			}
+			if e, ok := set.Max(); e != 40 || !ok {
+				t.Errorf("set.Max(); got: %v, %v; want: 40, true", e, ok)
+			}
+			for _, tt := range []struct {
+				elem                          uint32
+				floor, ceiling, lower, higher result
+			}{
+				{
+					elem:    5,
+					ceiling: result{10, true},
+					higher:  result{10, true},
+				},
+				{
+					elem:    10,
+					floor:   result{10, true},
+					ceiling: result{10, true},
+					higher:  result{20, true},
+				},
+				{
+					elem:    25,
+					floor:   result{20, true},
+					ceiling: result{30, true},
+					lower:   result{20, true},
+					higher:  result{30, true},
+				},
+				{
+					elem:    30,
+					floor:   result{30, true},
+					ceiling: result{30, true},
+					lower:   result{20, true},
+					higher:  result{40, true},
+				},
+				{
+					elem:  45,
+					floor: result{40, true},
+					lower: result{40, true},
+				},
+			} {
+				for _, fn := range []struct {
+					name string
+					call func(uint32) (uint32, bool)
+					want result
+				}{
+					{"Floor", set.Floor, tt.floor},
+					{"Ceiling", set.Ceiling, tt.ceiling},
+					{"Lower", set.Lower, tt.lower},
+					{"Higher", set.Higher, tt.higher},
+				} {
+					e, ok := fn.call(tt.elem)
+					if got := (result{e, ok}); got != fn.want {
+						t.Errorf("set.%s(%v); got: %v; want: %v", fn.name, tt.elem, got, fn.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSortedNavigationTies(t *testing.T) {
+	// Runes at even indices have identical pointers in the set,
+	// while runes at odd indices only have equal sort keys.
+	runes := runePtrsFrom("aabbbccdd")
+	set := NewSortedCmpEqFunc(cmpPtrVal[rune], equal[*rune], runes(0, 2, 4, 5, 8)...)
+	for _, tt := range []struct {
+		name string
+		call func(*rune) (*rune, bool)
+		elem *rune
+		want *rune
+	}{
+		{"Floor", set.Floor, runes(2)[0], runes(2)[0]},
+		{"Floor", set.Floor, runes(3)[0], runes(4)[0]},
+		{"Floor", set.Floor, runes(6)[0], runes(5)[0]},
+		{"Ceiling", set.Ceiling, runes(4)[0], runes(4)[0]},
+		{"Ceiling", set.Ceiling, runes(3)[0], runes(2)[0]},
+		{"Ceiling", set.Ceiling, runes(6)[0], runes(5)[0]},
+		{"Lower", set.Lower, runes(4)[0], runes(0)[0]},
+		{"Lower", set.Lower, runes(6)[0], runes(4)[0]},
+		{"Higher", set.Higher, runes(2)[0], runes(5)[0]},
+		{"Higher", set.Higher, runes(6)[0], runes(8)[0]},
+	} {
+		if got, ok := tt.call(tt.elem); got != tt.want || !ok {
+			t.Errorf("set.%s(%c@%p); got: %c@%p, %v; want: %c@%p, true", tt.name, *tt.elem, tt.elem, *got, got, ok, *tt.want, tt.want)
+		}
+	}
+}
+
+func TestSortedRanges(t *testing.T) {
+	for _, typ := range sortedTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet(50, 10, 40, 20, 30)
+			for _, tt := range []struct {
+				name string
+				got  Sorted[uint32]
+				want []uint32
+			}{
+				{"SubSet(20,40,Open)", set.SubSet(20, 40, Open), []uint32{30}},
+				{"SubSet(20,40,IncludeLo)", set.SubSet(20, 40, IncludeLo), []uint32{20, 30}},
+				{"SubSet(20,40,IncludeHi)", set.SubSet(20, 40, IncludeHi), []uint32{30, 40}},
+				{"SubSet(20,40,Closed)", set.SubSet(20, 40, Closed), []uint32{20, 30, 40}},
+				{"SubSet(15,45,Open)", set.SubSet(15, 45, Open), []uint32{20, 30, 40}},
+				{"SubSet(40,20,Closed)", set.SubSet(40, 20, Closed), nil},
+				{"SubSet(30,30,Open)", set.SubSet(30, 30, Open), nil},
+				{"HeadSet(30)", set.HeadSet(30), []uint32{10, 20}},
+				{"HeadSet(5)", set.HeadSet(5), nil},
+				{"TailSet(30)", set.TailSet(30), []uint32{30, 40, 50}},
+				{"TailSet(55)", set.TailSet(55), nil},
+			} {
+				if got := tt.got.Elems(); !slices.Equal(got, tt.want) {
+					t.Errorf("set.%s; got: %v; want: %v", tt.name, got, tt.want)
+				}
+			}
+			for _, tt := range []struct {
+				lo, hi uint32
+				want   int
+			}{
+				{0, 100, 5},
+				{10, 50, 4},
+				{15, 45, 3},
+				{30, 30, 0},
+				{40, 20, 0},
+			} {
+				if got := set.CountRange(tt.lo, tt.hi); got != tt.want {
+					t.Errorf("set.CountRange(%v, %v); got: %v; want: %v", tt.lo, tt.hi, got, tt.want)
+				}
+			}
+			// Make sure the view is a copy.
+			sub := set.SubSet(10, 50, Closed)
+			sub.Remove(30)
+			if !set.Contains(30) {
+				t.Errorf("set.Contains(30) after sub.Remove(30); got: false; want: true")
+			}
+		})
+	}
+}
+
+func TestSortedRankSelect(t *testing.T) {
+	for _, typ := range sortedTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			elems := []uint32{10, 20, 30, 40, 50}
+			set := typ.newSet(50, 10, 40, 20, 30)
+			for i, e := range elems {
+				if got := set.Rank(e); got != i {
+					t.Errorf("set.Rank(%v); got: %v; want: %v", e, got, i)
+				}
+				if got := set.Rank(e + 5); got != i+1 {
+					t.Errorf("set.Rank(%v); got: %v; want: %v", e+5, got, i+1)
+				}
+				if got := set.At(i); got != e {
+					t.Errorf("set.At(%v); got: %v; want: %v", i, got, e)
+				}
+			}
+			if got := set.Rank(0); got != 0 {
+				t.Errorf("set.Rank(0); got: %v; want: 0", got)
+			}
+			for _, tt := range []struct {
+				i, j int
+			}{
+				{0, 0},
+				{0, 5},
+				{1, 3},
+				{4, 5},
+				{5, 5},
+			} {
+				if got, want := set.Slice(tt.i, tt.j).Elems(), elems[tt.i:tt.j]; !slices.Equal(got, want) {
+					t.Errorf("set.Slice(%v, %v); got: %v; want: %v", tt.i, tt.j, got, want)
+				}
+			}
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("set.At(5); want panic")
+					}
+				}()
+				set.At(5)
+			}()
+		})
+	}
+}
+
+func TestSortedRankTies(t *testing.T) {
+	type item struct {
+		key, id int
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	eq := func(a, b item) bool { return a == b }
+	for _, tt := range []struct {
+		name   string
+		newSet func(elems ...item) Sorted[item]
+	}{
+		{"sorted", func(elems ...item) Sorted[item] { return NewSortedCmpEqFunc(byKey, eq, elems...) }},
+		{"btree", func(elems ...item) Sorted[item] { return NewSortedBTreeCmpEqFunc(byKey, eq, elems...) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := tt.newSet(item{0, 1}, item{1, 1}, item{1, 2}, item{1, 3}, item{2, 1})
+			for _, rt := range []struct {
+				elem item
+				want int
+			}{
+				{item{1, 1}, 1},
+				{item{1, 2}, 2},
+				{item{1, 3}, 3},
+				// Missing elements are ranked after the whole run of elements that sort equal.
+				{item{1, 4}, 4},
+				{item{1, 0}, 4},
+				{item{2, 2}, 5},
+			} {
+				if got := set.Rank(rt.elem); got != rt.want {
+					t.Errorf("set.Rank(%v); got: %v; want: %v", rt.elem, got, rt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSortedInsertSeq(t *testing.T) {
+	type item struct {
+		key, id int
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	eq := func(a, b item) bool { return a == b }
+	rng := rand.New(rand.NewSource(1))
+	randItems := func(n int) []item {
+		items := make([]item, n)
+		for i := range items {
+			items[i] = item{rng.Intn(20), rng.Intn(3)}
+		}
+		return items
+	}
+	for range 100 {
+		a, b := randItems(rng.Intn(30)), randItems(rng.Intn(30))
+
+		got := NewSortedCmpEqFunc(byKey, eq, a...)
+		InsertSeq(got, slices.Values(b))
+		want := NewSortedCmpEqFunc(byKey, eq, a...)
+		want.InsertAll(b...)
+		if !slices.Equal(got.Elems(), want.Elems()) {
+			t.Fatalf("InsertSeq(sorted, %v); got: %v; want: %v", b, got.Elems(), want.Elems())
+		}
+
+		keys := func(items []item) []int {
+			k := make([]int, len(items))
+			for i, it := range items {
+				k[i] = it.key
+			}
+			return k
+		}
+		gotKeys := NewSorted(keys(a)...)
+		InsertSeq(gotKeys, slices.Values(keys(b)))
+		if want := NewSorted(append(keys(a), keys(b)...)...); !slices.Equal(gotKeys.Elems(), want.Elems()) {
+			t.Fatalf("InsertSeq(ordered, %v); got: %v; want: %v", keys(b), gotKeys.Elems(), want.Elems())
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// An SQLEncoding is the form in which a set is stored in an SQL column.
+type SQLEncoding uint8
+
+const (
+	// SQLArray stores a set as a PostgreSQL array literal, like {a,b,"c d"}.
+	SQLArray SQLEncoding = iota
+	// SQLJSON stores a set as a JSON array. See JSON for details.
+	SQLJSON
+	// SQLText stores a set as separated text, like a,b,c.
+	SQLText
+)
+
+// SQL is a wrapper that stores a set in an SQL column.
+// It implements sql.Scanner and driver.Valuer.
+//
+// Sets are stored as text, with elements formatted in the same order as Flag.
+// A NULL value is scanned as an empty set and a nil set is stored as NULL.
+//
+// For example:
+//
+//	tags := sets.New[string]()
+//	err := db.QueryRow("SELECT tags FROM posts WHERE id = $1", id).Scan(&sets.SQL[string]{Set: tags})
+type SQL[E any] struct {
+	// Set is the set to store or into which to scan. Scanning replaces
+	// its elements, but preserves its type and comparison function.
+	// It must not be nil when scanning.
+	Set Set[E]
+	// Encoding is the form in which the set is stored.
+	Encoding SQLEncoding
+	// Parse parses an element of an array or text encoding.
+	// If it's nil, it's parsed like Flag.Parse.
+	Parse func(string) (E, error)
+	// Format formats an element of an array or text encoding.
+	// If it's nil, it's formatted like Flag.Format.
+	Format func(E) string
+	// Separator separates elements of a text encoding.
+	// If it's empty, a comma is used.
+	Separator string
+}
+
+// Value implements the driver.Valuer interface.
+func (s SQL[E]) Value() (driver.Value, error) {
+	if s.Set == nil {
+		return nil, nil
+	}
+	switch s.Encoding {
+	case SQLArray:
+		elems := formatElems(s.Set, s.Format)
+		var b strings.Builder
+		b.WriteByte('{')
+		for i, e := range elems {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeArrayElem(&b, e)
+		}
+		b.WriteByte('}')
+		return b.String(), nil
+	case SQLJSON:
+		b, err := marshalJSON(s.Set)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case SQLText:
+		return strings.Join(formatElems(s.Set, s.Format), s.separator()), nil
+	}
+	return nil, fmt.Errorf("sets: unknown SQL encoding: %d", s.Encoding)
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *SQL[E]) Scan(src any) error {
+	if s.Set == nil {
+		return errors.New("sets: SQL.Set is nil")
+	}
+	var text string
+	switch src := src.(type) {
+	case nil:
+		return replace(s.Set, nil, false)
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return fmt.Errorf("sets: can't scan %T into a set", src)
+	}
+	switch s.Encoding {
+	case SQLArray:
+		strs, err := parseArray(text)
+		if err != nil {
+			return err
+		}
+		parse := s.Parse
+		if parse == nil {
+			parse = parseElem[E]
+		}
+		elems := make([]E, len(strs))
+		for i, str := range strs {
+			if elems[i], err = parse(str); err != nil {
+				return fmt.Errorf("sets: invalid element %q: %w", str, err)
+			}
+		}
+		return replace(s.Set, elems, false)
+	case SQLJSON:
+		return (&JSON[E]{Set: s.Set}).UnmarshalJSON([]byte(text))
+	case SQLText:
+		elems, err := parseElems(text, s.separator(), s.Parse)
+		if err != nil {
+			return err
+		}
+		return replace(s.Set, elems, false)
+	}
+	return fmt.Errorf("sets: unknown SQL encoding: %d", s.Encoding)
+}
+
+func (s *SQL[E]) separator() string {
+	if s.Separator == "" {
+		return ","
+	}
+	return s.Separator
+}
+
+// writeArrayElem writes the element of an array literal, quoting it if necessary.
+func writeArrayElem(b *strings.Builder, elem string) {
+	if elem != "" && !strings.EqualFold(elem, "NULL") && !strings.ContainsAny(elem, "{},\"\\ \t\n\r\v\f") {
+		b.WriteString(elem)
+		return
+	}
+	b.WriteByte('"')
+	for _, r := range elem {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+}
+
+// parseArray returns the elements of a one-dimensional array literal.
+func parseArray(text string) ([]string, error) {
+	errInvalid := fmt.Errorf("sets: invalid array literal: %q", text)
+	text = strings.TrimSpace(text)
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, errInvalid
+	}
+	text = text[1 : len(text)-1]
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var elems []string
+	for {
+		text = strings.TrimLeft(text, " \t\n\r\v\f")
+		var elem strings.Builder
+		if strings.HasPrefix(text, `"`) {
+			i := 1
+			for ; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' {
+					i++
+				}
+				if i < len(text) {
+					elem.WriteByte(text[i])
+				}
+			}
+			if i >= len(text) {
+				return nil, errInvalid
+			}
+			text = strings.TrimLeft(text[i+1:], " \t\n\r\v\f")
+		} else {
+			i := strings.IndexAny(text, `,{}"`)
+			if i < 0 {
+				i = len(text)
+			}
+			str := strings.TrimSpace(text[:i])
+			if str == "" || strings.EqualFold(str, "NULL") {
+				return nil, errInvalid
+			}
+			elem.WriteString(str)
+			text = text[i:]
+		}
+		elems = append(elems, elem.String())
+		if text == "" {
+			return elems, nil
+		}
+		if text[0] != ',' {
+			return nil, errInvalid
+		}
+		text = text[1:]
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a database driver with a single column of a single row,
+// which is written by any Exec and read by any Query.
+type fakeDriver struct {
+	mu    sync.Mutex
+	value driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.value = args[0]
+	if v, ok := args[0].(string); ok {
+		s.d.value = []byte(v) // Text columns are often returned as bytes.
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{value: s.d.value}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"value"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+var registerFakeDriver = sync.OnceValue(func() string {
+	sql.Register("sets-fake", &fakeDriver{})
+	return "sets-fake"
+})
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open(registerFakeDriver(), "")
+	if err != nil {
+		t.Fatalf("sql.Open(); unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	for _, tt := range []struct {
+		name     string
+		encoding SQLEncoding
+		sep      string
+		elems    []string
+		want     string
+	}{
+		{"array", SQLArray, "", []string{"b", "a"}, "{a,b}"},
+		{"array/quoted", SQLArray, "", []string{"", "c d", `e"f`, `g\h`, "null", "{i}"}, `{"","c d","e\"f","g\\h","null","{i}"}`},
+		{"array/empty", SQLArray, "", nil, "{}"},
+		{"json", SQLJSON, "", []string{"b", "a"}, `["a","b"]`},
+		{"json/empty", SQLJSON, "", nil, `[]`},
+		{"text", SQLText, "", []string{"b", "a"}, "a,b"},
+		{"text/separator", SQLText, "|", []string{"b", "a"}, "a|b"},
+		{"text/empty", SQLText, "", nil, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			in := SQL[string]{Set: New(tt.elems...), Encoding: tt.encoding, Separator: tt.sep}
+			if _, err := db.Exec("INSERT", in); err != nil {
+				t.Fatalf("db.Exec(); unexpected error: %v", err)
+			}
+			var raw string
+			if err := db.QueryRow("SELECT").Scan(&raw); err != nil {
+				t.Fatalf("db.QueryRow().Scan(); unexpected error: %v", err)
+			}
+			if raw != tt.want {
+				t.Errorf("stored value; got: %s; want: %s", raw, tt.want)
+			}
+
+			set := NewSorted("x")
+			out := SQL[string]{Set: set, Encoding: tt.encoding, Separator: tt.sep}
+			if err := db.QueryRow("SELECT").Scan(&out); err != nil {
+				t.Fatalf("db.QueryRow().Scan(); unexpected error: %v", err)
+			}
+			want := slices.Sorted(slices.Values(tt.elems))
+			if got := set.Elems(); !slices.Equal(got, want) {
+				t.Errorf("set.Elems(); got: %q; want: %q", got, want)
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		if _, err := db.Exec("INSERT", SQL[int]{}); err != nil {
+			t.Fatalf("db.Exec(); unexpected error: %v", err)
+		}
+		set := New(1)
+		if err := db.QueryRow("SELECT").Scan(&SQL[int]{Set: set}); err != nil {
+			t.Fatalf("db.QueryRow().Scan(); unexpected error: %v", err)
+		}
+		if got := set.Len(); got != 0 {
+			t.Errorf("set.Len(); got: %v; want: 0", got)
+		}
+	})
+
+	t.Run("int", func(t *testing.T) {
+		if _, err := db.Exec("INSERT", SQL[int]{Set: New(3, -1, 2)}); err != nil {
+			t.Fatalf("db.Exec(); unexpected error: %v", err)
+		}
+		set := New[int]()
+		if err := db.QueryRow("SELECT").Scan(&SQL[int]{Set: set}); err != nil {
+			t.Fatalf("db.QueryRow().Scan(); unexpected error: %v", err)
+		}
+		if set.Len() != 3 || !set.ContainsAll(-1, 2, 3) {
+			t.Errorf("set.Elems(); got: %v; want: [-1 2 3]", set.Elems())
+		}
+	})
+}
+
+func TestSQLScanInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		encoding SQLEncoding
+		src      any
+	}{
+		{"type", SQLText, 1},
+		{"array/braces", SQLArray, "a,b"},
+		{"array/null", SQLArray, "{a,NULL}"},
+		{"array/nested", SQLArray, "{{a},{b}}"},
+		{"array/unterminated", SQLArray, `{"a}`},
+		{"array/trailing", SQLArray, `{"a"b}`},
+		{"array/parse", SQLArray, "{1,x}"},
+		{"json", SQLJSON, "{}"},
+		{"text/parse", SQLText, "1,x"},
+		{"encoding", SQLEncoding(99), "1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			set := New(7)
+			if err := (&SQL[int]{Set: set, Encoding: tt.encoding}).Scan(tt.src); err == nil {
+				t.Errorf("Scan(%v); expected error", tt.src)
+			}
+			if !set.Contains(7) || set.Len() != 1 {
+				t.Errorf("set.Elems() after error; got: %v; want: [7]", set.Elems())
+			}
+		})
+	}
+	for _, tt := range []struct {
+		encoding SQLEncoding
+		src      string
+	}{
+		{SQLArray, "{-1}"},
+		{SQLJSON, "[-1]"},
+		{SQLText, "-1"},
+	} {
+		bits := NewBitSet(7)
+		if err := (&SQL[int]{Set: bits, Encoding: tt.encoding}).Scan(tt.src); err == nil {
+			t.Errorf("Scan(%q) into bit set; expected error", tt.src)
+		}
+		if got := bits.Elems(); !slices.Equal(got, []int{7}) {
+			t.Errorf("bits.Elems() after error; got: %v; want: [7]", got)
+		}
+	}
+	if err := (&SQL[int]{}).Scan("{}"); err == nil {
+		t.Errorf("Scan(nil set); expected error")
+	}
+	if _, err := (SQL[int]{Set: New[int](), Encoding: SQLEncoding(99)}).Value(); err == nil {
+		t.Errorf("Value(unknown encoding); expected error")
+	}
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// A Concurrent is a set that is safe for concurrent use by multiple goroutines.
+//
+// Operations involving two concurrent sets lock both of them in a consistent order,
+// so they observe a consistent view of both sets and don't deadlock.
+// Range and All iterate over a snapshot of the elements, so the set may be
+// modified during iteration.
+type Concurrent[E any] interface {
+	Set[E]
+
+	// Update calls fn with the underlying set while holding an exclusive lock,
+	// so that multiple changes are applied atomically.
+	// The set must not be retained by fn or used after fn returns
+	// and fn must not use the concurrent set.
+	Update(fn func(set Set[E]))
+}
+
+// Synchronized returns a concurrent set backed by the given set.
+// The given set must not be used directly afterwards.
+func Synchronized[E any](set Set[E]) Concurrent[E] {
+	if set, ok := set.(*synchronized[E]); ok {
+		return set
+	}
+	return &synchronized[E]{
+		id:  syncID.Add(1),
+		set: set,
+	}
+}
+
+// NewConcurrent returns a concurrent set initialized with the given elements.
+func NewConcurrent[E comparable](elems ...E) Concurrent[E] {
+	return Synchronized(New(elems...))
+}
+
+// syncID is used to assign a unique lock order to each synchronized set.
+var syncID atomic.Uint64
+
+type synchronized[E any] struct {
+	mu  sync.RWMutex
+	id  uint64
+	set Set[E]
+}
+
+func (set *synchronized[E]) Contains(elem E) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.set.Contains(elem)
+}
+
+func (set *synchronized[E]) ContainsAll(elems ...E) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.set.ContainsAll(elems...)
+}
+
+func (set *synchronized[E]) ContainsSet(other Set[E]) bool {
+	other, unlock := set.lockWith(other, false)
+	defer unlock()
+	return set.set.ContainsSet(other)
+}
+
+func (set *synchronized[E]) Insert(elem E) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.set.Insert(elem)
+}
+
+func (set *synchronized[E]) InsertAll(elems ...E) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.set.InsertAll(elems...)
+}
+
+func (set *synchronized[E]) InsertSet(other Set[E]) {
+	other, unlock := set.lockWith(other, true)
+	defer unlock()
+	set.set.InsertSet(other)
+}
+
+func (set *synchronized[E]) insertSeq(seq iter.Seq[E]) {
+	// Collect the elements before locking the set,
+	// in case the sequence iterates over the set itself.
+	elems := slices.Collect(seq)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	InsertSeq(set.set, slices.Values(elems))
+}
+
+func (set *synchronized[E]) Remove(elem E) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.set.Remove(elem)
+}
+
+func (set *synchronized[E]) RemoveAll(elems ...E) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.set.RemoveAll(elems...)
+}
+
+func (set *synchronized[E]) RemoveSet(other Set[E]) {
+	other, unlock := set.lockWith(other, true)
+	defer unlock()
+	set.set.RemoveSet(other)
+}
+
+func (set *synchronized[E]) Intersection(other Set[E]) Set[E] {
+	other, unlock := set.lockWith(other, false)
+	defer unlock()
+	return Synchronized(set.set.Intersection(other))
+}
+
+func (set *synchronized[E]) Union(other Set[E]) Set[E] {
+	other, unlock := set.lockWith(other, false)
+	defer unlock()
+	return Synchronized(set.set.Union(other))
+}
+
+func (set *synchronized[E]) Difference(other Set[E]) Set[E] {
+	other, unlock := set.lockWith(other, false)
+	defer unlock()
+	return Synchronized(set.set.Difference(other))
+}
+
+func (set *synchronized[E]) SymmetricDifference(other Set[E]) Set[E] {
+	other, unlock := set.lockWith(other, false)
+	defer unlock()
+	return Synchronized(set.set.SymmetricDifference(other))
+}
+
+func (set *synchronized[E]) Len() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.set.Len()
+}
+
+func (set *synchronized[E]) Elems() []E {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.set.Elems()
+}
+
+func (set *synchronized[E]) Range(fn func(v E) bool) {
+	for _, v := range set.Elems() {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+func (set *synchronized[E]) All() iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for _, v := range set.Elems() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func (set *synchronized[E]) Clone() Set[E] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return Synchronized(set.set.Clone())
+}
+
+func (set *synchronized[E]) Update(fn func(set Set[E])) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	fn(set.set)
+}
+
+// lockWith locks the set for writing or reading and, if other is a synchronized set,
+// locks other for reading. The sets are locked in a consistent order to prevent deadlocks.
+// It returns the set to use in place of other and a function to unlock the sets.
+func (set *synchronized[E]) lockWith(other Set[E], write bool) (Set[E], func()) {
+	lock, unlock := set.mu.RLock, set.mu.RUnlock
+	if write {
+		lock, unlock = set.mu.Lock, set.mu.Unlock
+	}
+	o, ok := other.(*synchronized[E])
+	switch {
+	case !ok:
+		lock()
+		return other, unlock
+	case o == set:
+		lock()
+		return set.set, unlock
+	case set.id < o.id:
+		lock()
+		o.mu.RLock()
+	default:
+		o.mu.RLock()
+		lock()
+	}
+	return o.set, func() {
+		o.mu.RUnlock()
+		unlock()
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestSynchronizedCrossOperations(t *testing.T) {
+	a := NewConcurrent[int]()
+	b := NewConcurrent[int]()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range 200 {
+				// Operate on both sets in opposite orders.
+				x, y := a, b
+				if i%2 == 1 {
+					x, y = b, a
+				}
+				x.Insert(i*1000 + k)
+				x.InsertSet(y)
+				x.ContainsSet(y)
+				x.Union(y)
+				x.RemoveSet(x.Difference(y).Intersection(y))
+				InsertSeq(x, x.All())
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := a.Union(b).Len(), 8*200; got != want {
+		t.Fatalf("a.Union(b).Len(); got: %v; want: %v", got, want)
+	}
+}
+
+func TestSynchronizedUpdate(t *testing.T) {
+	set := NewConcurrent[int]()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range 100 {
+				set.Update(func(s Set[int]) {
+					// Pairs of elements are always inserted and removed together.
+					s.Insert(2 * (i*100 + k))
+					s.Insert(2*(i*100+k) + 1)
+					if k%2 == 1 {
+						s.Remove(2 * (i*100 + k - 1))
+						s.Remove(2*(i*100+k-1) + 1)
+					}
+				})
+				if n := set.Len(); n%2 != 0 {
+					t.Errorf("set.Len(); got: %v; want even", n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := set.Len(), 8*100; got != want {
+		t.Fatalf("set.Len(); got: %v; want: %v", got, want)
+	}
+}
+
+func TestSynchronizedSorted(t *testing.T) {
+	set := Synchronized[int](NewSorted(3, 1, 2))
+	if set != Synchronized[int](set) {
+		t.Fatalf("Synchronized(set) wrapped an already synchronized set")
+	}
+	if got, want := set.Union(NewConcurrent(4)).Elems(), []int{1, 2, 3, 4}; !slices.Equal(got, want) {
+		t.Fatalf("set.Union(...).Elems(); got: %v; want: %v", got, want)
+	}
+}
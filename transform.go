@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"iter"
+	"slices"
+)
+
+// Filter returns a new set with the elements of the set for which pred returns true.
+// The new set is of the same kind as the set, with the same comparison, equality,
+// key, or hash functions.
+func Filter[E any](set Set[E], pred func(E) bool) Set[E] {
+	s := emptyOf(set)
+	InsertSeq(s, func(yield func(E) bool) {
+		for e := range set.All() {
+			if pred(e) && !yield(e) {
+				return
+			}
+		}
+	})
+	return s
+}
+
+// Map returns a new set with the results of calling fn with each element of the set.
+//
+// If E and F are the same type and the set can hold the results, the new set is of the
+// same kind as the set, like Filter. A bit set can't hold negative results, for example.
+// Otherwise, if the set is Sorted and the underlying type of F is an integer, float,
+// or string, the new set is sorted in the natural order of F. If the set is Ordered,
+// the new set is also Ordered and if the set is Concurrent, the new set is also
+// Concurrent. Otherwise, the new set is like New.
+func Map[E any, F comparable](set Set[E], fn func(E) F) Set[F] {
+	elems := make([]F, 0, set.Len())
+	for e := range set.All() {
+		elems = append(elems, fn(e))
+	}
+	s := mappedOf(set, elems)
+	InsertSeq(s, slices.Values(elems))
+	return s
+}
+
+// FlatMap returns a new set with the elements of the sequences returned by calling fn
+// with each element of the set. The new set is of the same kind as the set returned by Map.
+func FlatMap[E any, F comparable](set Set[E], fn func(E) iter.Seq[F]) Set[F] {
+	var elems []F
+	for e := range set.All() {
+		for f := range fn(e) {
+			elems = append(elems, f)
+		}
+	}
+	s := mappedOf(set, elems)
+	InsertSeq(s, slices.Values(elems))
+	return s
+}
+
+// Partition returns new sets with the elements of the set for which pred returns
+// true and false, respectively. The new sets are of the same kind as the set, like Filter.
+func Partition[E any](set Set[E], pred func(E) bool) (yes, no Set[E]) {
+	var a, b []E
+	for e := range set.All() {
+		if pred(e) {
+			a = append(a, e)
+		} else {
+			b = append(b, e)
+		}
+	}
+	yes, no = emptyOf(set), emptyOf(set)
+	InsertSeq(yes, slices.Values(a))
+	InsertSeq(no, slices.Values(b))
+	return yes, no
+}
+
+// GroupBy returns new sets with the elements of the set grouped by the result of calling
+// key with each element. The new sets are of the same kind as the set, like Filter.
+// It returns an empty map if the set is empty.
+func GroupBy[E any, K comparable](set Set[E], key func(E) K) map[K]Set[E] {
+	elems := make(map[K][]E)
+	for e := range set.All() {
+		k := key(e)
+		elems[k] = append(elems[k], e)
+	}
+	groups := make(map[K]Set[E], len(elems))
+	for k, v := range elems {
+		s := emptyOf(set)
+		InsertSeq(s, slices.Values(v))
+		groups[k] = s
+	}
+	return groups
+}
+
+// Reduce returns the result of calling fn with the accumulated value, starting with init,
+// and each element of the set. The elements of sorted and ordered sets are reduced in order.
+func Reduce[E, A any](set Set[E], init A, fn func(acc A, elem E) A) A {
+	acc := init
+	for e := range set.All() {
+		acc = fn(acc, e)
+	}
+	return acc
+}
+
+// Any returns a value indicating if pred returns true for any element of the set.
+// It returns false if the set is empty.
+func Any[E any](set Set[E], pred func(E) bool) bool {
+	for e := range set.All() {
+		if pred(e) {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns a value indicating if pred returns true for every element of the set.
+// It returns true if the set is empty.
+func All[E any](set Set[E], pred func(E) bool) bool {
+	for e := range set.All() {
+		if !pred(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// emptyOf returns an empty set of the same kind as the set.
+func emptyOf[E any](set Set[E]) Set[E] {
+	if s, ok := set.(emptier[E]); ok {
+		return s.emptySet()
+	}
+	s := set.Clone()
+	s.RemoveAll(s.Elems()...)
+	return s
+}
+
+// mappedOf returns an empty set for the results of Map or FlatMap over the set.
+func mappedOf[E any, F comparable](set Set[E], results []F) Set[F] {
+	if s, ok := any(set).(Set[F]); ok {
+		if s := emptyOf(s); validateAll(s, results) == nil {
+			return s
+		}
+	}
+	switch set.(type) {
+	case Sorted[E]:
+		if s := newNaturalSorted[F](); s != nil {
+			return s
+		}
+	case Ordered[E]:
+		return NewOrdered[F]()
+	case Concurrent[E]:
+		return NewConcurrent[F]()
+	}
+	return New[F]()
+}
+
+// newNaturalSorted returns an empty sorted set in the natural order of E
+// if its underlying type is an integer, float, or string, and otherwise nil.
+// Sets of the predeclared types compare elements with cmp.Compare directly.
+func newNaturalSorted[E any]() Sorted[E] {
+	var s any
+	switch any(*new(E)).(type) {
+	case int:
+		s = NewSorted[int]()
+	case int8:
+		s = NewSorted[int8]()
+	case int16:
+		s = NewSorted[int16]()
+	case int32:
+		s = NewSorted[int32]()
+	case int64:
+		s = NewSorted[int64]()
+	case uint:
+		s = NewSorted[uint]()
+	case uint8:
+		s = NewSorted[uint8]()
+	case uint16:
+		s = NewSorted[uint16]()
+	case uint32:
+		s = NewSorted[uint32]()
+	case uint64:
+		s = NewSorted[uint64]()
+	case uintptr:
+		s = NewSorted[uintptr]()
+	case float32:
+		s = NewSorted[float32]()
+	case float64:
+		s = NewSorted[float64]()
+	case string:
+		s = NewSorted[string]()
+	}
+	if s, ok := s.(Sorted[E]); ok {
+		return s
+	}
+	if cmp := naturalCmp[E](); cmp != nil {
+		return NewSortedCmpFunc(cmp)
+	}
+	return nil
+}
+
+type emptier[E any] interface {
+	// emptySet returns an empty set of the same kind with the same parameters.
+	emptySet() Set[E]
+}
+
+func (set table[E]) emptySet() Set[E] {
+	return make(table[E])
+}
+
+func (set *ordered[E]) emptySet() Set[E] {
+	return &ordered[E]{}
+}
+
+func (set *sorted[E]) emptySet() Set[E] {
+	return &sorted[E]{cmp: set.cmp, eq: set.eq}
+}
+
+func (set *btree[E]) emptySet() Set[E] {
+	return &btree[E]{cmp: set.cmp, eq: set.eq}
+}
+
+func (set *bitset[E]) emptySet() Set[E] {
+	return &bitset[E]{}
+}
+
+func (set *roaring[E]) emptySet() Set[E] {
+	return &roaring[E]{}
+}
+
+func (set *linked[E]) emptySet() Set[E] {
+	return newLinked[E](0)
+}
+
+func (set *keyed[E, K]) emptySet() Set[E] {
+	return set.empty()
+}
+
+func (set *hashed[E]) emptySet() Set[E] {
+	return set.empty()
+}
+
+func (set *sharded[E]) emptySet() Set[E] {
+	return newSharded[E](len(set.shards))
+}
+
+func (set *persistentSet[E]) emptySet() Set[E] {
+	return &persistentSet[E]{}
+}
+
+func (set *synchronized[E]) emptySet() Set[E] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return Synchronized(emptyOf(set.set))
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright 2023 Andrew Bursavich. All rights reserved.
+// Use of this source code is governed by The MIT License
+// which can be found in the LICENSE file.
+
+package sets
+
+import (
+	"cmp"
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func reverseInt(a, b int) int { return cmp.Compare(b, a) }
+
+var transformTypes = []struct {
+	name   string
+	newSet func(elems ...int) Set[int]
+}{
+	{"table", New[int]},
+	{"ordered", func(elems ...int) Set[int] { return NewSorted(elems...) }},
+	{"sorted", func(elems ...int) Set[int] { return NewSortedCmpFunc(reverseInt, elems...) }},
+	{"btree", func(elems ...int) Set[int] { return NewSortedBTreeCmpFunc(reverseInt, elems...) }},
+	{"bitset", func(elems ...int) Set[int] { return NewBitSet(elems...) }},
+	{"linked", func(elems ...int) Set[int] { return NewOrdered(elems...) }},
+	{"keyed", func(elems ...int) Set[int] { return NewKeyFunc(strconv.Itoa, elems...) }},
+	{"hashed", func(elems ...int) Set[int] { return NewHashFunc(hashComparable[int], equal[int], elems...) }},
+	{"concurrent", func(elems ...int) Set[int] { return NewConcurrent(elems...) }},
+	{"sharded", func(elems ...int) Set[int] { return NewSharded(2, elems...) }},
+	{"persistent", func(elems ...int) Set[int] { return NewPersistent(elems...).AsSet() }},
+	{"external", func(elems ...int) Set[int] { return &externalSet[int]{New(elems...)} }},
+}
+
+// sameKind returns a value indicating if the sets are of the same type,
+// except that the external set's Clone returns the set it wraps.
+func sameKind(got, set Set[int]) bool {
+	if s, ok := set.(*externalSet[int]); ok {
+		set = s.Set
+	}
+	return reflect.TypeOf(got) == reflect.TypeOf(set)
+}
+
+func TestFilter(t *testing.T) {
+	for _, typ := range transformTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet(5, 2, 8, 3, 6)
+			got := Filter(set, func(e int) bool { return e%2 == 0 })
+			if !sameKind(got, set) {
+				t.Fatalf("Filter(...) type; got: %T; want: %T", got, set)
+			}
+			if want := NewSorted(2, 6, 8); !Equal[int](got, want) {
+				t.Fatalf("Filter(...); got: %v; want: %v", got.Elems(), want.Elems())
+			}
+			if _, ok := set.(Sorted[int]); ok || typ.name == "linked" {
+				want := slices.DeleteFunc(set.Elems(), func(e int) bool { return e%2 != 0 })
+				if got := got.Elems(); !slices.Equal(got, want) {
+					t.Fatalf("Filter(...) order; got: %v; want: %v", got, want)
+				}
+			}
+			if got := set.Len(); got != 5 {
+				t.Fatalf("set.Len() after Filter(...); got: %v; want: 5", got)
+			}
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	for _, typ := range transformTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet(1, 2, 3)
+			same := Map(set, func(e int) int { return e * 10 })
+			if !sameKind(same, set) {
+				t.Fatalf("Map(...) type; got: %T; want: %T", same, set)
+			}
+			if want := NewSorted(10, 20, 30); !Equal[int](same, want) {
+				t.Fatalf("Map(...); got: %v; want: %v", same.Elems(), want.Elems())
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		set  Set[int]
+		want []string
+	}{
+		{"sorted", NewSortedCmpFunc(reverseInt, 3, 12, 1), []string{"1", "12", "3"}},
+		{"linked", NewOrdered(3, 12, 1), []string{"3", "12", "1"}},
+	} {
+		t.Run(tt.name+"/strings", func(t *testing.T) {
+			got := Map(tt.set, strconv.Itoa)
+			if got := got.Elems(); !slices.Equal(got, tt.want) {
+				t.Fatalf("Map(...); got: %v; want: %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name string
+		set  Set[int]
+		kind func(Set[int]) bool
+	}{
+		{"bitset", NewBitSet(1, 2), func(s Set[int]) bool { _, ok := s.(Sorted[int]); return ok }},
+		{"concurrent", Synchronized(NewBitSet(1, 2)), func(s Set[int]) bool { _, ok := s.(Concurrent[int]); return ok }},
+	} {
+		t.Run(tt.name+"/negative", func(t *testing.T) {
+			got := Map(tt.set, func(e int) int { return -e })
+			if !tt.kind(got) {
+				t.Fatalf("Map(...) type; got: %T; want: same interface as %T", got, tt.set)
+			}
+			if want := NewSorted(-2, -1); !Equal(got, want) {
+				t.Fatalf("Map(...); got: %v; want: %v", got.Elems(), want.Elems())
+			}
+			flat := FlatMap(tt.set, func(e int) iter.Seq[int] { return slices.Values([]int{e, -e}) })
+			if want := NewSorted(-2, -1, 1, 2); !Equal(flat, want) {
+				t.Fatalf("FlatMap(...); got: %v; want: %v", flat.Elems(), want.Elems())
+			}
+		})
+	}
+
+	if _, ok := Map(NewConcurrent(1, 2), strconv.Itoa).(Concurrent[string]); !ok {
+		t.Fatalf("Map(concurrent, ...); got: not Concurrent; want: Concurrent")
+	}
+	// Results of predeclared types are compared with cmp.Compare directly.
+	if _, ok := Map(NewBitSet(3, 12, 1), strconv.Itoa).(*ordered[string]); !ok {
+		t.Fatalf("Map(bitset, strconv.Itoa); got: not NewSorted; want: NewSorted")
+	}
+	type label string
+	labels := Map(NewBitSet(3, 12, 1), func(e int) label { return label(strconv.Itoa(e)) })
+	if got, want := labels.Elems(), []label{"1", "12", "3"}; !slices.Equal(got, want) {
+		t.Fatalf("Map(bitset, label); got: %v; want: %v", got, want)
+	}
+	type pair struct{ a, b int }
+	got := Map(NewSorted(1, 2), func(e int) pair { return pair{e, -e} })
+	if got.Len() != 2 || !got.ContainsAll(pair{1, -1}, pair{2, -2}) {
+		t.Fatalf("Map(sorted, pair); got: %v; want: [{1 -1} {2 -2}]", got.Elems())
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	set := NewSorted(3, 1, 2)
+	got := FlatMap(set, func(e int) iter.Seq[int] { return slices.Values([]int{e * 2, e * 3}) })
+	if want := []int{2, 3, 4, 6, 9}; !slices.Equal(got.Elems(), want) {
+		t.Fatalf("FlatMap(...); got: %v; want: %v", got.Elems(), want)
+	}
+	strs := FlatMap(NewOrdered("cb", "ab"), func(s string) iter.Seq[rune] { return slices.Values([]rune(s)) })
+	if want := []rune("cba"); !slices.Equal(strs.Elems(), want) {
+		t.Fatalf("FlatMap(...); got: %q; want: %q", strs.Elems(), want)
+	}
+}
+
+func TestPartition(t *testing.T) {
+	for _, typ := range transformTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet(5, 2, 8, 3, 6)
+			yes, no := Partition(set, func(e int) bool { return e%2 == 0 })
+			for _, tt := range []struct {
+				name string
+				got  Set[int]
+				want Set[int]
+			}{
+				{"yes", yes, NewSorted(2, 6, 8)},
+				{"no", no, NewSorted(3, 5)},
+			} {
+				if !sameKind(tt.got, set) {
+					t.Fatalf("Partition(...) %s type; got: %T; want: %T", tt.name, tt.got, set)
+				}
+				if !Equal(tt.got, tt.want) {
+					t.Fatalf("Partition(...) %s; got: %v; want: %v", tt.name, tt.got.Elems(), tt.want.Elems())
+				}
+			}
+		})
+	}
+	yes, no := Partition(NewSortedCmpFunc(reverseInt, 1, 2, 3, 4), func(e int) bool { return e > 2 })
+	if got, want := yes.Elems(), []int{4, 3}; !slices.Equal(got, want) {
+		t.Fatalf("Partition(sorted, ...) yes; got: %v; want: %v", got, want)
+	}
+	if got, want := no.Elems(), []int{2, 1}; !slices.Equal(got, want) {
+		t.Fatalf("Partition(sorted, ...) no; got: %v; want: %v", got, want)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	for _, typ := range transformTypes {
+		t.Run(typ.name, func(t *testing.T) {
+			set := typ.newSet(1, 2, 3, 4, 5, 6, 7)
+			groups := GroupBy(set, func(e int) int { return e % 3 })
+			want := map[int]Set[int]{
+				0: NewSorted(3, 6),
+				1: NewSorted(1, 4, 7),
+				2: NewSorted(2, 5),
+			}
+			if got := len(groups); got != len(want) {
+				t.Fatalf("len(GroupBy(...)); got: %v; want: %v", got, len(want))
+			}
+			for k, w := range want {
+				g := groups[k]
+				if g == nil {
+					t.Fatalf("GroupBy(...)[%v]; got: nil; want: %v", k, w.Elems())
+				}
+				if !sameKind(g, set) {
+					t.Fatalf("GroupBy(...)[%v] type; got: %T; want: %T", k, g, set)
+				}
+				if !Equal(g, w) {
+					t.Fatalf("GroupBy(...)[%v]; got: %v; want: %v", k, g.Elems(), w.Elems())
+				}
+			}
+		})
+	}
+	if got := GroupBy(New[int](), func(e int) int { return e }); got == nil || len(got) != 0 {
+		t.Fatalf("GroupBy(empty, ...); got: %v; want: map[]", got)
+	}
+}
+
+func TestReduce(t *testing.T) {
+	set := NewSortedCmpFunc(reverseInt, 1, 2, 3)
+	got := Reduce(set, "", func(acc string, e int) string { return acc + strconv.Itoa(e) })
+	if want := "321"; got != want {
+		t.Fatalf("Reduce(...); got: %v; want: %v", got, want)
+	}
+	if got := Reduce(New[int](), 7, func(acc, e int) int { return acc + e }); got != 7 {
+		t.Fatalf("Reduce(empty, ...); got: %v; want: 7", got)
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	set := New(2, 4, 5)
+	even := func(e int) bool { return e%2 == 0 }
+	positive := func(e int) bool { return e > 0 }
+	if !Any(set, even) {
+		t.Errorf("Any(set, even); got: false; want: true")
+	}
+	if All(set, even) {
+		t.Errorf("All(set, even); got: true; want: false")
+	}
+	if !All(set, positive) {
+		t.Errorf("All(set, positive); got: false; want: true")
+	}
+	if Any(New[int](), positive) {
+		t.Errorf("Any(empty, positive); got: true; want: false")
+	}
+	if !All(New[int](), even) {
+		t.Errorf("All(empty, even); got: false; want: true")
+	}
+}